@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"reflect"
 	"testing"
 )
 
@@ -387,3 +388,81 @@ func TestArrayConstructorParsing(t *testing.T) {
 		})
 	}
 }
+
+// TestArrayConstructorEvaluation tests that array constructors create typed slices
+func TestArrayConstructorEvaluation(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	locator := ast.NewStandardTypeLocator()
+	locator.RegisterType("com.example.Address", reflect.TypeOf(&Address{}))
+	context := ast.NewStandardEvaluationContext()
+	context.SetTypeLocator(locator)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"IntSize", "new int[3]", []int32{0, 0, 0}},
+		{"IntSizeExpression", "new int[1 + 1]", []int32{0, 0}},
+		{"IntInitializer", "new int[]{1, 2, 3}", []int32{1, 2, 3}},
+		{"SizeAndInitializer", "new int[2]{4, 5}", []int32{4, 5}},
+		{"EmptyInitializer", "new int[]{}", []int32{}},
+		{"StringFromNumbers", "new String[]{1, 2, 3}", []string{"1", "2", "3"}},
+		{"QualifiedString", "new java.lang.String[]{'a'}", []string{"a"}},
+		{"Double", "new double[]{1d, 2d}", []float64{1, 2}},
+		{"Boolean", "new boolean[2]", []bool{false, false}},
+		{"Char", "new char[]{'a', 'b'}", []rune{'a', 'b'}},
+		{"Long", "new long[]{1, 2}", []int64{1, 2}},
+		{"MultiDimensional", "new int[2][3]", [][]int32{{0, 0, 0}, {0, 0, 0}}},
+		{"MultiDimensionalInitializer", "new int[][]{{1, 2}, {3}}", [][]int32{{1, 2}, {3}}},
+		{"RegisteredType", "new com.example.Address[1]", []*Address{nil}},
+		{"Index", "new String[]{'123'}[0]", "123"},
+		{"Length", "new String[]{'a', 'b', 'c', 'd'}.length", 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expression '%s': expected %#v, got %#v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestArrayConstructorEvaluationErrors tests invalid sizes and initializers
+func TestArrayConstructorEvaluationErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	errorCases := []struct {
+		name       string
+		expression string
+	}{
+		{"InitializerLengthMismatch", "new char[7]{'a','c','d','e'}"},
+		{"TooManyElements", "new int[1024 * 1024][1024 * 1024]"},
+		{"NegativeSize", "new int[-1]"},
+		{"NonNumericSize", "new int['abc']"},
+		{"UnconvertibleElement", "new int[]{'abc'}"},
+		{"UnknownElementType", "new com.example.Unknown[2]"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValue(); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/FactoryBeanAccessTests.java
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// UserService is a bean that looks up users by id
+type UserService struct {
+	users map[int]*User
+}
+
+func (s *UserService) FindById(id int) (*User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("no user with id %d", id)
+	}
+	return user, nil
+}
+
+// UserServiceFactory creates UserService beans, like a Spring FactoryBean
+type UserServiceFactory struct{}
+
+func (f *UserServiceFactory) GetObjectType() string {
+	return "UserService"
+}
+
+// recordingBeanResolver remembers the bean names it was asked for
+type recordingBeanResolver struct {
+	names []string
+}
+
+func (r *recordingBeanResolver) Resolve(context ast.EvaluationContext, beanName string) (interface{}, error) {
+	r.names = append(r.names, beanName)
+	return beanName, nil
+}
+
+func newBeanContext() *ast.StandardEvaluationContext {
+	resolver := ast.NewMapBeanResolver(map[string]interface{}{
+		"userService": &UserService{users: map[int]*User{123: newTestUser()}},
+		"car":         "Colour: red",
+	})
+	resolver.RegisterFactoryBean("userService", &UserServiceFactory{})
+	resolver.RegisterBean("my.service", "dotted")
+
+	context := ast.NewStandardEvaluationContext()
+	context.SetBeanResolver(resolver)
+	return context
+}
+
+// TestBeanReference tests @bean and &bean references against a map backed resolver
+func TestBeanReference(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newBeanContext()
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"SimpleBean", "@car", "Colour: red"},
+		{"QuotedName", "@'my.service'", "dotted"},
+		{"MethodOnBean", "@userService.findById(123).name", "Nikola Tesla"},
+		{"FactoryBean", "&userService.objectType", "UserService"},
+		{"BeanInExpression", "@car + '!'", "Colour: red!"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestFactoryBeanReference tests that factory bean references keep their flag
+// and pass the & prefix to the resolver
+func TestFactoryBeanReference(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	resolver := &recordingBeanResolver{}
+	context := ast.NewStandardEvaluationContext()
+	context.SetBeanResolver(resolver)
+
+	for _, tc := range []struct {
+		expression  string
+		factoryBean bool
+		beanName    string
+	}{
+		{"@foo", false, "foo"},
+		{"&foo", true, "&foo"},
+		{"&'foo.bar'", true, "&foo.bar"},
+	} {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+		}
+		reference, ok := expr.AST.(*ast.BeanReference)
+		if !ok {
+			t.Fatalf("Expected a BeanReference for '%s', got %T", tc.expression, expr.AST)
+		}
+		if reference.FactoryBean != tc.factoryBean {
+			t.Errorf("Expression '%s': expected FactoryBean %v", tc.expression, tc.factoryBean)
+		}
+
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+		}
+		if result != tc.beanName {
+			t.Errorf("Expression '%s': expected resolver to be asked for '%s', got %v", tc.expression, tc.beanName, result)
+		}
+	}
+}
+
+// TestBeanReferenceErrors tests missing resolvers, unknown beans and failing bean methods
+func TestBeanReferenceErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	errorCases := []struct {
+		name       string
+		expression string
+		context    ast.EvaluationContext
+	}{
+		{"NoResolver", "@car", ast.NewStandardEvaluationContext()},
+		{"UnknownBean", "@truck", newBeanContext()},
+		{"UnknownFactoryBean", "&car", newBeanContext()},
+		{"BeanMethodError", "@userService.findById(7)", newBeanContext()},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValueWithContext(tc.context); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ package main
 // 已全部测试完毕
 
 import (
+	"errors"
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"strings"
 	"testing"
 )
 
@@ -464,3 +466,96 @@ func TestBooleanErrorCases(t *testing.T) {
 		})
 	}
 }
+
+// TestBooleanOperandConversion 测试严格模式下操作数的布尔转换及错误位置
+func TestBooleanOperandConversion(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContext()
+	context.SetVariables(map[string]interface{}{"flag": "true", "off": "off", "nothing": nil})
+
+	conversionTests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"#flag and true", true},
+		{"!#off", true},
+		{"#off or 'yes'", true},
+		{"#flag ? true : false", true},
+		{"false and #nothing", false},
+		{"true or #nothing", true},
+	}
+	for _, tc := range conversionTests {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("解析表达式 '%s' 失败: %v", tc.expression, err)
+		}
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("求值 '%s' 失败: %v", tc.expression, err)
+		}
+		if result != tc.expected {
+			t.Errorf("表达式 '%s': 期望 %v, 实际 %v", tc.expression, tc.expected, result)
+		}
+	}
+
+	errorTests := []struct {
+		expression string
+		position   string
+	}{
+		{"true and 'hello'", "(position 9)"},
+		{"1 or false", "(position 0)"},
+		{"not 35.2", "(position 4)"},
+		{"#nothing ? 1 : 2", "(position 0)"},
+		{"true and (false or #nothing)", "(position 19)"},
+	}
+	for _, tc := range errorTests {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("解析表达式 '%s' 失败: %v", tc.expression, err)
+		}
+		result, err := expr.GetValueWithContext(context)
+		if !errors.Is(err, ast.ErrCannotConvertToBoolean) {
+			t.Errorf("表达式 '%s': 期望布尔转换错误, 实际 %v", tc.expression, err)
+			continue
+		}
+		if result != nil {
+			t.Errorf("表达式 '%s': 出错时期望结果为 nil, 实际 %v", tc.expression, result)
+		}
+		if !strings.Contains(err.Error(), tc.position) {
+			t.Errorf("表达式 '%s': 错误应包含 '%s', 实际 %v", tc.expression, tc.position, err)
+		}
+	}
+}
+
+// TestLenientBooleans 测试宽松模式下非布尔操作数按真值处理
+func TestLenientBooleans(t *testing.T) {
+	config := ast.NewSpelParserConfiguration()
+	config.LenientBooleans = true
+	parser := ast.NewSpelExpressionParserWithConfig(config)
+
+	lenientTests := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"'hello' and 'goodbye'", true},
+		{"'' or false", false},
+		{"!35.2", false},
+		{"!0", true},
+		{"null and true", false},
+		{"null ? 1 : 2", int32(2)},
+		{"'x' ? 1 : 2", int32(1)},
+	}
+	for _, tc := range lenientTests {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("解析表达式 '%s' 失败: %v", tc.expression, err)
+		}
+		result, err := expr.GetValue()
+		if err != nil {
+			t.Fatalf("求值 '%s' 失败: %v", tc.expression, err)
+		}
+		if result != tc.expected {
+			t.Errorf("表达式 '%s': 期望 %v, 实际 %v", tc.expression, tc.expected, result)
+		}
+	}
+}
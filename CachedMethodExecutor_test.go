@@ -3,8 +3,10 @@ package main
 // 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/CachedMethodExecutorTests.java
 
 import (
+	"errors"
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"reflect"
 	"testing"
 )
 
@@ -154,6 +156,7 @@ func TestCachedExecutionForParameters(t *testing.T) {
 	}
 
 	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContextWithRoot(&RootObject{BaseObject: &BaseObject{}})
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -167,23 +170,16 @@ func TestCachedExecutionForParameters(t *testing.T) {
 				t.Fatalf("解析表达式失败: %v", err)
 			}
 
-			// 注意：在实际实现中，这里需要设置变量和上下文
-			// 由于当前的 Go 实现可能还没有完整的变量上下文支持，
-			// 这里主要验证表达式解析的正确性
+			context.SetVariable("var", tc.variable)
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("求值失败: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("结果不匹配: 期望 %v, 实际 %v", tc.expected, result)
+			}
 
-			fmt.Printf("解析结果: %s\n", expr.GetExpressionString())
-			fmt.Printf("期望结果: %s\n", tc.expected)
-
-			// 在有完整上下文支持后，可以添加实际的求值测试
-			// result, err := expr.GetValueWithContext(context)
-			// if err != nil {
-			//     t.Fatalf("求值失败: %v", err)
-			// }
-			// if result != tc.expected {
-			//     t.Errorf("结果不匹配: 期望 %v, 实际 %v", tc.expected, result)
-			// }
-
-			fmt.Println("✅ 表达式解析成功")
+			fmt.Printf("求值结果: %v\n", result)
 		})
 	}
 }
@@ -231,6 +227,7 @@ func TestCachedExecutionForTarget(t *testing.T) {
 	}
 
 	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContext()
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -244,28 +241,212 @@ func TestCachedExecutionForTarget(t *testing.T) {
 				t.Fatalf("解析表达式失败: %v", err)
 			}
 
-			// 注意：在实际实现中，这里需要设置目标对象和上下文
-			// 由于当前的 Go 实现可能还没有完整的对象上下文支持，
-			// 这里主要验证表达式解析的正确性
+			context.SetVariable("var", tc.target)
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("求值失败: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("结果不匹配: 期望 %v, 实际 %v", tc.expected, result)
+			}
 
-			fmt.Printf("解析结果: %s\n", expr.GetExpressionString())
-			fmt.Printf("期望结果: %s\n", tc.expected)
-
-			// 在有完整上下文支持后，可以添加实际的求值测试
-			// context.SetVariable("var", tc.target)
-			// result, err := expr.GetValueWithContext(context)
-			// if err != nil {
-			//     t.Fatalf("求值失败: %v", err)
-			// }
-			// if result != tc.expected {
-			//     t.Errorf("结果不匹配: 期望 %v, 实际 %v", tc.expected, result)
-			// }
-
-			fmt.Println("✅ 表达式解析成功")
+			fmt.Printf("求值结果: %v\n", result)
 		})
 	}
 }
 
+// TestCachedExecutorInvalidation 测试同一表达式重复求值时复用缓存的执行器，
+// 并在 #var 的类型变化时重新解析方法
+func TestCachedExecutorInvalidation(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	resolver := &countingMethodResolver{delegate: ast.NewReflectiveMethodResolver()}
+	context := ast.NewStandardEvaluationContextWithRoot(&RootObject{BaseObject: &BaseObject{}})
+	context.AddMethodResolver(resolver)
+
+	expr, err := parser.ParseExpression("echo(#var)")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+
+	steps := []struct {
+		variable         interface{}
+		expected         string
+		expectedResolves int
+	}{
+		{42, "int: 42", 1},
+		{42, "int: 42", 1},
+		{"Deep Thought", "String: Deep Thought", 2},
+		{"Deep Thought", "String: Deep Thought", 2},
+		{42, "int: 42", 3},
+	}
+
+	for i, step := range steps {
+		context.SetVariable("var", step.variable)
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("第 %d 次求值失败: %v", i+1, err)
+		}
+		if result != step.expected {
+			t.Errorf("第 %d 次求值: 期望 %v, 实际 %v", i+1, step.expected, result)
+		}
+		if resolver.resolves != step.expectedResolves {
+			t.Errorf("第 %d 次求值: 期望解析 %d 次, 实际 %d 次", i+1, step.expectedResolves, resolver.resolves)
+		}
+	}
+
+	// 目标对象类型变化同样会使缓存失效
+	expr, err = parser.ParseExpression("#var.echo(42)")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+	resolver.resolves = 0
+	targets := []struct {
+		target   interface{}
+		expected string
+	}{
+		{&RootObject{BaseObject: &BaseObject{}}, "int: 42"},
+		{&BaseObject{}, "String: 42"},
+		{&RootObject{BaseObject: &BaseObject{}}, "int: 42"},
+	}
+	for _, target := range targets {
+		context.SetVariable("var", target.target)
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("求值失败: %v", err)
+		}
+		if result != target.expected {
+			t.Errorf("目标 %T: 期望 %v, 实际 %v", target.target, target.expected, result)
+		}
+	}
+	if resolver.resolves != len(targets) {
+		t.Errorf("期望解析 %d 次, 实际 %d 次", len(targets), resolver.resolves)
+	}
+}
+
+// countingMethodResolver counts how often methods are resolved
+type countingMethodResolver struct {
+	delegate ast.MethodResolver
+	resolves int
+}
+
+func (r *countingMethodResolver) Resolve(context ast.EvaluationContext, target interface{}, name string, argumentTypes []reflect.Type) (ast.MethodExecutor, error) {
+	r.resolves++
+	return r.delegate.Resolve(context, target, name, argumentTypes)
+}
+
+// denyingMethodResolver resolves no method at all, like a sandboxed context
+type denyingMethodResolver struct{}
+
+func (denyingMethodResolver) Resolve(context ast.EvaluationContext, target interface{}, name string, argumentTypes []reflect.Type) (ast.MethodExecutor, error) {
+	return nil, nil
+}
+
+// sandboxedContext replaces the method resolvers of a standard context
+type sandboxedContext struct {
+	*ast.StandardEvaluationContext
+}
+
+func (c sandboxedContext) GetMethodResolvers() []ast.MethodResolver {
+	return []ast.MethodResolver{denyingMethodResolver{}}
+}
+
+// TestCachedExecutorPerResolvers 测试缓存的执行器不会被解析器不同的上下文复用
+func TestCachedExecutorPerResolvers(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	expr, err := parser.ParseExpression("echo(42)")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+
+	permissive := ast.NewStandardEvaluationContextWithRoot(&RootObject{BaseObject: &BaseObject{}})
+	if result, err := expr.GetValueWithContext(permissive); err != nil || result != "int: 42" {
+		t.Fatalf("期望 int: 42, 实际 %v (%v)", result, err)
+	}
+
+	sandboxed := sandboxedContext{ast.NewStandardEvaluationContextWithRoot(&RootObject{BaseObject: &BaseObject{}})}
+	if result, err := expr.GetValueWithContext(sandboxed); err == nil {
+		t.Errorf("期望沙箱上下文拒绝方法调用, 实际 %v", result)
+	}
+
+	// 回到原来的上下文后仍可调用
+	if result, err := expr.GetValueWithContext(permissive); err != nil || result != "int: 42" {
+		t.Errorf("期望 int: 42, 实际 %v (%v)", result, err)
+	}
+}
+
+// TestCachedExecutorPerTypeDescriptor 测试 T(...) 的静态函数执行器不会被另一类型复用
+func TestCachedExecutorPerTypeDescriptor(t *testing.T) {
+	locator := ast.NewEmptyTypeLocator()
+	if err := locator.RegisterFunctions("First", map[string]interface{}{
+		"twice": func(value int32) string { return fmt.Sprintf("First: %d", 2*value) },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := locator.RegisterFunctions("Second", map[string]interface{}{
+		"twice": func(value int64) string { return fmt.Sprintf("Second: %d", 2*value) },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := locator.FindType("First")
+	second, _ := locator.FindType("Second")
+
+	parser := ast.NewSpelExpressionParser()
+	expr, err := parser.ParseExpression("#type.twice(21)")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+	context := ast.NewStandardEvaluationContext()
+	for _, step := range []struct {
+		descriptor *ast.TypeDescriptor
+		expected   string
+	}{
+		{first, "First: 42"},
+		{second, "Second: 42"},
+		{first, "First: 42"},
+	} {
+		context.SetVariable("type", step.descriptor)
+		if result, err := expr.GetValueWithContext(context); err != nil || result != step.expected {
+			t.Errorf("%s: 期望 %s, 实际 %v (%v)", step.descriptor.Name(), step.expected, result, err)
+		}
+	}
+}
+
+// failingMethodResolver fails every method lookup
+type failingMethodResolver struct{}
+
+func (failingMethodResolver) Resolve(context ast.EvaluationContext, target interface{}, name string, argumentTypes []reflect.Type) (ast.MethodExecutor, error) {
+	return nil, errors.New("lookup failed")
+}
+
+type failingResolverContext struct {
+	*ast.StandardEvaluationContext
+}
+
+func (c failingResolverContext) GetMethodResolvers() []ast.MethodResolver {
+	return []ast.MethodResolver{failingMethodResolver{}}
+}
+
+// TestMethodResolverErrorPosition 测试解析器返回的错误带有方法调用的位置
+func TestMethodResolverErrorPosition(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	expr, err := parser.ParseExpression("'abc'.concat('d')")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+
+	_, err = expr.GetValueWithContext(failingResolverContext{ast.NewStandardEvaluationContext()})
+	var spelErr *ast.SpelEvaluationException
+	if !errors.As(err, &spelErr) {
+		t.Fatalf("期望 SpelEvaluationException, 实际 %v", err)
+	}
+	if spelErr.Message != ast.PROBLEM_LOCATING_METHOD || spelErr.Position != 5 {
+		t.Errorf("期望 PROBLEM_LOCATING_METHOD 位于 5, 实际 %v 位于 %d", spelErr.Message, spelErr.Position)
+	}
+	if spelErr.Cause == nil || spelErr.Cause.Error() != "lookup failed" {
+		t.Errorf("期望保留解析器的错误, 实际 %v", spelErr.Cause)
+	}
+}
+
 // TestMethodCachingBehavior 测试方法缓存行为
 func TestMethodCachingBehavior(t *testing.T) {
 	fmt.Println("\n=== 方法缓存行为测试 ===")
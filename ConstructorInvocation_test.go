@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"strings"
 	"testing"
 )
 
@@ -927,3 +928,97 @@ func TestConstructorInMethodChain(t *testing.T) {
 		})
 	}
 }
+
+// TestConstructorEvaluation tests new expressions against the constructor resolvers
+func TestConstructorEvaluation(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	resolver := ast.NewStandardConstructorResolver()
+	if err := resolver.RegisterConstructor("com.example.PlaceOfBirth", func(city string) *PlaceOfBirth {
+		return &PlaceOfBirth{city: city}
+	}); err != nil {
+		t.Fatalf("Failed to register constructor: %v", err)
+	}
+	context := ast.NewStandardEvaluationContext()
+	context.AddConstructorResolver(resolver)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"StringWithArgument", "new String('hello world')", "hello world"},
+		{"EmptyString", "new String()", ""},
+		{"QualifiedString", "new java.lang.String('abc')", "abc"},
+		{"DoubleFromInt", "new Double(3)", 3.0},
+		{"DoubleFromLong", "new Double(3L)", 3.0},
+		{"DoubleFromReal", "new Double(3.5)", 3.5},
+		{"IntegerFromString", "new Integer('12')", int32(12)},
+		{"BooleanFromString", "new Boolean('true')", true},
+		{"RegisteredType", "new com.example.PlaceOfBirth('Smiljan').city", "Smiljan"},
+		{"ArgumentExpression", "new com.example.PlaceOfBirth('Smil' + 'jan').getCity()", "Smiljan"},
+		{"EmptyArrayList", "new java.util.ArrayList().length", 0},
+		{"CopiedArrayList", "new java.util.ArrayList({1, 2})[1]", int32(2)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v (%T), got %v (%T)", tc.expression, tc.expected, tc.expected, result, result)
+			}
+		})
+	}
+}
+
+// TestConstructorEvaluationErrors tests unknown types, bad arguments and forbidden construction
+func TestConstructorEvaluationErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	sandbox := ast.NewEmptyConstructorResolver()
+	sandbox.SetUnknownTypeError(func(typeName string) error {
+		return fmt.Errorf("construction of '%s' is not allowed", typeName)
+	})
+	sandboxContext := ast.NewStandardEvaluationContext()
+	sandboxContext.SetConstructorResolvers(sandbox)
+
+	errorCases := []struct {
+		name       string
+		expression string
+		context    ast.EvaluationContext
+	}{
+		{"UnknownType", "new com.example.Unknown()", ast.NewStandardEvaluationContext()},
+		{"NoMatchingConstructor", "new String('a', 'b')", ast.NewStandardEvaluationContext()},
+		{"ConstructorError", "new Integer('abc')", ast.NewStandardEvaluationContext()},
+		{"NegativeCapacity", "new java.util.ArrayList(-1)", ast.NewStandardEvaluationContext()},
+		{"SandboxedObject", "new String('abc')", sandboxContext},
+		{"SandboxedArray", "new int[3]", sandboxContext},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValueWithContext(tc.context); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+
+	expr, err := parser.ParseExpression("new String('abc')")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	_, err = expr.GetValueWithContext(sandboxContext)
+	if err == nil || !strings.Contains(err.Error(), "construction of 'String' is not allowed") {
+		t.Errorf("Expected the sandbox error, got %v", err)
+	}
+}
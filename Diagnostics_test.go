@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// TestParseWithDiagnostics tests that parsing goes on after errors, reporting
+// each of them and keeping the rest of the tree
+func TestParseWithDiagnostics(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	type diagnostic struct {
+		message  ast.SpelMessage
+		position int
+	}
+	testCases := []struct {
+		expression  string
+		ast         string
+		diagnostics []diagnostic
+	}{
+		{"foo(1 +, 2, bar(3 *))", "foo(<error>, 2, bar(<error>))", []diagnostic{
+			{ast.RIGHT_OPERAND_PROBLEM, 6}, {ast.RIGHT_OPERAND_PROBLEM, 18}}},
+		{"{1, , 3}", "{1,<error>,3}", []diagnostic{{ast.NOT_EXPECTED_TOKEN, 4}}},
+		{"a[1 +].b(,)", "a[<error>].b(<error>)", []diagnostic{
+			{ast.RIGHT_OPERAND_PROBLEM, 4}, {ast.NOT_EXPECTED_TOKEN, 9}}},
+		{"foo(1 2, 3)", "foo(1)", []diagnostic{{ast.NOT_EXPECTED_TOKEN, 6}}},
+		{"[1, 2 3]", "{1,2}", []diagnostic{{ast.NOT_EXPECTED_TOKEN, 6}}},
+		{"#f(1, (2 +)) + {x: (y +)}", "(#f(1, <error>) + {x:<error>})", []diagnostic{
+			{ast.RIGHT_OPERAND_PROBLEM, 9}, {ast.RIGHT_OPERAND_PROBLEM, 22}}},
+		{"1 2", "1", []diagnostic{{ast.MORE_INPUT, 2}}},
+		{"1 +", "<error>", []diagnostic{{ast.RIGHT_OPERAND_PROBLEM, 2}}},
+		{"foo(", "<error>", []diagnostic{{ast.RUN_OUT_OF_ARGUMENTS, 4}}},
+		{"'abc", "<error>", []diagnostic{{ast.NON_TERMINATING_QUOTED_STRING, 0}}},
+		{"1 + foo(2)", "(1 + foo(2))", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, diagnostics := parser.ParseWithDiagnostics(tc.expression)
+			if expr == nil {
+				t.Fatalf("Expression '%s': expected a partial tree", tc.expression)
+			}
+			if result := expr.ToStringAST(); result != tc.ast {
+				t.Errorf("Expression '%s': expected AST %s, got %s", tc.expression, tc.ast, result)
+			}
+			if len(diagnostics) != len(tc.diagnostics) {
+				t.Fatalf("Expression '%s': expected %d diagnostics, got %v", tc.expression, len(tc.diagnostics), diagnostics)
+			}
+			for i, expected := range tc.diagnostics {
+				d := diagnostics[i]
+				if d.Message != expected.message || d.Position != expected.position || d.Severity != ast.SeverityError {
+					t.Errorf("Expression '%s': expected error %v at position %d, got %s %v at position %d",
+						tc.expression, expected.message, expected.position, d.Severity, d.Message, d.Position)
+				}
+			}
+		})
+	}
+}
+
+// TestParseWithDiagnosticsMatchesParseExpression tests that the error returned
+// by ParseExpression is among the diagnostics
+func TestParseWithDiagnosticsMatchesParseExpression(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	expressions := []string{
+		"1 +", "true and )", "> 3", "?:1", "1 2", ")", "(1", "x ? 1", "x ? 1 2", "{1, }",
+		"foo(", "name.1", "new String", "new int[]", "list.?[]", "@", "\"abc", "1 | 2", "3 ~ 4",
+	}
+
+	for _, expression := range expressions {
+		t.Run(expression, func(t *testing.T) {
+			_, err := parser.ParseExpression(expression)
+			var parseErr *ast.SpelParseException
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expression '%s': expected a SpelParseException, got %v", expression, err)
+			}
+
+			_, diagnostics := parser.ParseWithDiagnostics(expression)
+			for _, d := range diagnostics {
+				if d.Message == parseErr.Message && d.Position == parseErr.Position {
+					return
+				}
+			}
+			t.Errorf("Expression '%s': expected diagnostic %v at position %d, got %v",
+				expression, parseErr.Message, parseErr.Position, diagnostics)
+		})
+	}
+}
+
+// TestErrorNode tests the placeholder of text that failed to parse
+func TestErrorNode(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	expr, diagnostics := parser.ParseWithDiagnostics("{1,\n 2 *}")
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %v", diagnostics)
+	}
+	if result := diagnostics[0].String(); result != "error 2:4: problem parsing right operand of '*'" {
+		t.Errorf("Unexpected diagnostic: %s", result)
+	}
+
+	errorNode, ok := expr.AST.GetChildren()[1].(*ast.ErrorNode)
+	if !ok {
+		t.Fatalf("Expected an ErrorNode, got %T", expr.AST.GetChildren()[1])
+	}
+	if errorNode.Text != "2 *" || errorNode.GetSourceSpan().String() != "2:2-2:5" {
+		t.Errorf("Expected the text '2 *' at 2:2-2:5, got '%s' at %s", errorNode.Text, errorNode.GetSourceSpan())
+	}
+
+	// Evaluating the partial tree reports the parse error
+	_, err := expr.GetValue()
+	var evalErr *ast.SpelEvaluationException
+	if !errors.As(err, &evalErr) || evalErr.Message != ast.RIGHT_OPERAND_PROBLEM || evalErr.Position != 7 {
+		t.Errorf("Expected RIGHT_OPERAND_PROBLEM at position 7, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// TestStandardEvaluationContext tests the configuration held by StandardEvaluationContext
+func TestStandardEvaluationContext(t *testing.T) {
+	t.Run("RootObject", func(t *testing.T) {
+		context := ast.NewStandardEvaluationContextWithRoot("root")
+		if context.GetRootObject().Value != "root" {
+			t.Errorf("Expected root object 'root', got %v", context.GetRootObject().Value)
+		}
+
+		context.SetRootObject(42)
+		if context.GetRootObject().Value != 42 {
+			t.Errorf("Expected root object 42, got %v", context.GetRootObject().Value)
+		}
+	})
+
+	t.Run("Variables", func(t *testing.T) {
+		context := ast.NewStandardEvaluationContext()
+		context.SetVariable("favouriteColour", "orange")
+		if context.LookupVariable("favouriteColour") != "orange" {
+			t.Errorf("Expected variable 'orange', got %v", context.LookupVariable("favouriteColour"))
+		}
+
+		// Setting a variable to nil removes it
+		context.SetVariable("favouriteColour", nil)
+		if context.LookupVariable("favouriteColour") != nil {
+			t.Errorf("Expected variable to be removed, got %v", context.LookupVariable("favouriteColour"))
+		}
+	})
+
+	t.Run("RegisterFunction", func(t *testing.T) {
+		context := ast.NewStandardEvaluationContext()
+		if err := context.RegisterFunction("upper", strings.ToUpper); err != nil {
+			t.Fatalf("Failed to register function: %v", err)
+		}
+		if _, ok := context.LookupFunction("upper"); !ok {
+			t.Error("Expected registered function to be found")
+		}
+
+		if err := context.RegisterFunction("notAFunction", "abc"); err == nil {
+			t.Error("Expected error when registering a non-function")
+		}
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		context := ast.NewStandardEvaluationContext()
+		if context.GetTypeLocator() == nil {
+			t.Error("Expected a default type locator")
+		}
+		if context.GetTypeConverter() == nil {
+			t.Error("Expected a default type converter")
+		}
+		if context.GetBeanResolver() != nil {
+			t.Error("Expected no default bean resolver")
+		}
+	})
+}
+
+// TestGetValueWithContext tests evaluating expressions against an evaluation context
+func TestGetValueWithContext(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	context := ast.NewStandardEvaluationContext()
+	context.SetVariable("favouriteColour", "orange")
+
+	expr, err := parser.ParseExpression("#favouriteColour")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+
+	result, err := expr.GetValueWithContext(context)
+	if err != nil {
+		t.Fatalf("Failed to evaluate expression: %v", err)
+	}
+	if result != "orange" {
+		t.Errorf("Expected 'orange', got %v", result)
+	}
+
+	// Unknown variables evaluate to nil
+	expr, err = parser.ParseExpression("#unknown")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	result, err = expr.GetValueWithContextAndRoot(context, "root")
+	if err != nil {
+		t.Fatalf("Failed to evaluate expression: %v", err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil for unknown variable, got %v", result)
+	}
+}
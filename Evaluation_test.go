@@ -47,10 +47,10 @@ func TestEvaluationMiscellaneous(t *testing.T) {
 		}{
 			{"'Andy'?:'Dave'", "Andy"},
 			{"null?:'Dave'", "Dave"},
-			{"3?:1", int64(3)},
-			{"(2*3)?:1*10", int64(6)},
-			{"null?:2*10", int64(20)},
-			{"(null?:1)*10", int64(10)},
+			{"3?:1", int32(3)},
+			{"(2*3)?:1*10", int32(6)},
+			{"null?:2*10", int32(20)},
+			{"(null?:1)*10", int32(10)},
 		}
 
 		for _, tc := range testCases {
@@ -207,10 +207,10 @@ func TestRelationalOperators(t *testing.T) {
 		{"LessThanOrEqual", "3 <= 6", true},
 		{"GreaterThanOrEqual1", "3 >= 6", false},
 		{"GreaterThanOrEqual2", "3 >= 3", true},
-		{"InstanceofString", "'xyz' instanceof T(String)", false},
+		{"InstanceofString", "'xyz' instanceof T(String)", true},
 		{"InstanceofInt", "'xyz' instanceof T(int)", false},
-		{"InstanceofNull1", "null instanceof T(String)", true},
-		{"InstanceofNull2", "null instanceof T(Integer)", true},
+		{"InstanceofNull1", "null instanceof T(String)", false},
+		{"InstanceofNull2", "null instanceof T(Integer)", false},
 	}
 
 	for _, tc := range testCases {
@@ -345,8 +345,8 @@ func TestUnaryOperators(t *testing.T) {
 		expression string
 		expected   interface{}
 	}{
-		{"UnaryMinus", "-5", int64(-5)},
-		{"UnaryPlus", "+5", int64(5)},
+		{"UnaryMinus", "-5", int32(-5)},
+		{"UnaryPlus", "+5", int32(5)},
 		{"UnaryNotTrue", "!true", false},
 		{"UnaryNotFalse", "!false", true},
 	}
@@ -395,12 +395,12 @@ func TestTernaryOperator(t *testing.T) {
 		expression string
 		expected   interface{}
 	}{
-		{"BasicTernary1", "2>4?1:2", int64(2)},
-		{"BasicTernary2", "'abc'=='abc'?1:2", int64(1)},
+		{"BasicTernary1", "2>4?1:2", int32(2)},
+		{"BasicTernary2", "'abc'=='abc'?1:2", int32(1)},
 		{"NestedTernary", "2>4?(3>2?true:false):(5<3?true:false)", false},
-		{"TernaryWithImplicitGrouping1", "4 % 2 == 0 ? 2 : 3 * 10", int64(2)},
-		{"TernaryWithImplicitGrouping2", "4 % 2 == 1 ? 2 : 3 * 10", int64(30)},
-		{"TernaryWithExplicitGrouping", "((4 % 2 == 0) ? 2 : 1) * 10", int64(20)},
+		{"TernaryWithImplicitGrouping1", "4 % 2 == 0 ? 2 : 3 * 10", int32(2)},
+		{"TernaryWithImplicitGrouping2", "4 % 2 == 1 ? 2 : 3 * 10", int32(30)},
+		{"TernaryWithExplicitGrouping", "((4 % 2 == 0) ? 2 : 1) * 10", int32(20)},
 	}
 
 	for _, tc := range testCases {
@@ -509,10 +509,10 @@ func TestArithmeticOperations(t *testing.T) {
 		expression string
 		expected   interface{}
 	}{
-		{"BasicArithmetic", "3*4+5", int64(17)},
-		{"AdvancedNumerics1", "2.0 * 3e0 * 4", int64(24)},
-		{"AdvancedNumerics2", "-2 ^ 4", int64(16)},
-		{"ComplexArithmetic", "1+2-3*8^2/2/2", int64(-45)},
+		{"BasicArithmetic", "3*4+5", int32(17)},
+		{"AdvancedNumerics1", "2.0 * 3e0 * 4", 24.0},
+		{"AdvancedNumerics2", "-2 ^ 4", int32(16)},
+		{"ComplexArithmetic", "1+2-3*8^2/2/2", int32(-45)},
 	}
 
 	for _, tc := range testCases {
@@ -591,6 +591,144 @@ func TestLogicalOperators(t *testing.T) {
 	}
 }
 
+// Counter holds the numeric fields incremented by TestIncrementDecrement
+type Counter struct {
+	Count int
+	Small int8
+	Big   int64
+	Ratio float64
+	Items []int
+	Calls int
+}
+
+// Next counts its calls and returns the counter itself
+func (c *Counter) Next() *Counter {
+	c.Calls++
+	return c
+}
+
+// Last counts its calls and returns the index of the last item
+func (c *Counter) Last() int {
+	c.Calls++
+	return len(c.Items) - 1
+}
+
+// TestIncrementDecrement tests prefix and postfix ++/-- writing back to their operand
+func TestIncrementDecrement(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		expression string
+		expected   interface{}
+		after      interface{}
+		read       func(counter *Counter) interface{}
+	}{
+		{"count++", 5, 6, func(c *Counter) interface{} { return c.Count }},
+		{"++count", 6, 6, func(c *Counter) interface{} { return c.Count }},
+		{"count--", 5, 4, func(c *Counter) interface{} { return c.Count }},
+		{"--count", 4, 4, func(c *Counter) interface{} { return c.Count }},
+		{"count++ + count", 11, 6, func(c *Counter) interface{} { return c.Count }},
+		{"small++", int8(127), int8(-128), func(c *Counter) interface{} { return c.Small }},
+		{"++big", int64(11), int64(11), func(c *Counter) interface{} { return c.Big }},
+		{"ratio--", 1.5, 0.5, func(c *Counter) interface{} { return c.Ratio }},
+		{"items[1]++", 2, 3, func(c *Counter) interface{} { return c.Items[1] }},
+		{"--items[0]", 0, 0, func(c *Counter) interface{} { return c.Items[0] }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			counter := &Counter{Count: 5, Small: 127, Big: 10, Ratio: 1.5, Items: []int{1, 2}}
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithRoot(counter)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v (%T), got %v (%T)", tc.expression, tc.expected, tc.expected, result, result)
+			}
+			if after := tc.read(counter); after != tc.after {
+				t.Errorf("Expression '%s': expected operand to be %v afterwards, got %v", tc.expression, tc.after, after)
+			}
+		})
+	}
+
+	t.Run("Variable", func(t *testing.T) {
+		context := ast.NewStandardEvaluationContext()
+		context.SetVariable("i", 1)
+		expr, err := parser.ParseExpression("#i++")
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if result, err := expr.GetValueWithContext(context); err != nil || result != 1 {
+			t.Errorf("Expected 1, got %v (%v)", result, err)
+		}
+		if context.LookupVariable("i") != 2 {
+			t.Errorf("Expected #i to be 2, got %v", context.LookupVariable("i"))
+		}
+	})
+
+	// The operand is resolved once, as in Spring, so its target and index
+	// are not evaluated again for the write
+	singleCalls := []struct {
+		expression string
+		after      interface{}
+		read       func(counter *Counter) interface{}
+	}{
+		{"next().count++", 6, func(c *Counter) interface{} { return c.Count }},
+		{"--next().count", 4, func(c *Counter) interface{} { return c.Count }},
+		{"items[last()]++", 3, func(c *Counter) interface{} { return c.Items[1] }},
+		{"next().items[next().last()]--", 1, func(c *Counter) interface{} { return c.Items[1] }},
+	}
+	for _, tc := range singleCalls {
+		t.Run("SingleEvaluation_"+tc.expression, func(t *testing.T) {
+			counter := &Counter{Count: 5, Items: []int{1, 2}}
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValueWithRoot(counter); err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if after := tc.read(counter); after != tc.after {
+				t.Errorf("Expression '%s': expected operand to be %v afterwards, got %v", tc.expression, tc.after, after)
+			}
+			calls := strings.Count(tc.expression, "(")
+			if counter.Calls != calls {
+				t.Errorf("Expression '%s': expected %d method calls, got %d", tc.expression, calls, counter.Calls)
+			}
+		})
+	}
+
+	t.Run("ASTString", func(t *testing.T) {
+		for _, expression := range []string{"count++", "++count", "count--", "--count"} {
+			expr, err := parser.ParseExpression(expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+			}
+			if expr.ToStringAST() != expression {
+				t.Errorf("Expected AST string '%s', got '%s'", expression, expr.ToStringAST())
+			}
+		}
+	})
+
+	errorCases := []string{"++5", "5++", "--'abc'", "#root++", "name++"}
+	for _, expression := range errorCases {
+		t.Run("Error_"+expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+			}
+			if _, err := expr.GetValueWithRoot(newTestUser()); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", expression)
+			}
+		})
+	}
+}
+
 // Helper function to run a basic evaluation test
 func runEvaluationTest(t *testing.T, parser *ast.SpelExpressionParser, expression string, expected interface{}) {
 	expr, err := parser.ParseExpression(expression)
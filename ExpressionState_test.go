@@ -0,0 +1,136 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/ExpressionStateTests.java
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// ScopeRoot is the root object used by the scope tests
+type ScopeRoot struct {
+	Threshold int
+	Numbers   []int
+	Nested    [][]int
+	Members   []*Member
+}
+
+func (r *ScopeRoot) GetValue() string {
+	return "root value"
+}
+
+// Member is an element type whose methods are called inside projections
+type Member struct {
+	Name  string
+	Title string
+}
+
+func (m *Member) Greet(name string) string {
+	return "Hello " + name
+}
+
+// TestExpressionStateActiveContextObject tests the active context object stack
+func TestExpressionStateActiveContextObject(t *testing.T) {
+	state := ast.NewExpressionStateWithRoot(ast.NewSpelParserConfiguration(), ast.NewTypedValue("root"))
+	if state.GetActiveContextObject().Value != "root" {
+		t.Errorf("Expected root as active context object, got %v", state.GetActiveContextObject().Value)
+	}
+
+	state.PushActiveContextObject(ast.NewTypedValue("element"))
+	if state.GetActiveContextObject().Value != "element" {
+		t.Errorf("Expected pushed active context object, got %v", state.GetActiveContextObject().Value)
+	}
+	if state.GetRootObject().Value != "root" {
+		t.Errorf("Expected root object to be unchanged, got %v", state.GetRootObject().Value)
+	}
+
+	state.PopActiveContextObject()
+	if state.GetActiveContextObject().Value != "root" {
+		t.Errorf("Expected root after pop, got %v", state.GetActiveContextObject().Value)
+	}
+}
+
+// TestExpressionStateScopes tests local variable scopes and scope roots
+func TestExpressionStateScopes(t *testing.T) {
+	context := ast.NewStandardEvaluationContextWithRoot("root")
+	context.SetVariable("name", "global")
+	state := ast.NewExpressionStateWithContext(context, nil, ast.NewSpelParserConfiguration())
+
+	if state.LookupVariable("name") != "global" {
+		t.Errorf("Expected context variable, got %v", state.LookupVariable("name"))
+	}
+
+	state.PushActiveContextObject(ast.NewTypedValue("element"))
+	state.EnterScopeWithVariables(map[string]interface{}{"name": "local"})
+	if state.LookupVariable("name") != "local" {
+		t.Errorf("Expected local variable to shadow context variable, got %v", state.LookupVariable("name"))
+	}
+	if state.GetScopeRootContextObject().Value != "element" {
+		t.Errorf("Expected scope root to be the active context object, got %v", state.GetScopeRootContextObject().Value)
+	}
+
+	state.EnterScope()
+	state.SetLocalVariable("inner", 1)
+	if state.LookupVariable("name") != "local" {
+		t.Errorf("Expected outer scope variable to be visible, got %v", state.LookupVariable("name"))
+	}
+	state.ExitScope()
+	if _, ok := state.LookupLocalVariable("inner"); ok {
+		t.Error("Expected inner variable to be discarded with its scope")
+	}
+
+	state.ExitScope()
+	state.PopActiveContextObject()
+	if state.LookupVariable("name") != "global" {
+		t.Errorf("Expected context variable after exiting scope, got %v", state.LookupVariable("name"))
+	}
+	if state.GetScopeRootContextObject().Value != "root" {
+		t.Errorf("Expected root as scope root, got %v", state.GetScopeRootContextObject().Value)
+	}
+}
+
+// TestThisAndRoot tests #this and #root inside and outside selection and projection
+func TestThisAndRoot(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContextWithRoot(&ScopeRoot{
+		Threshold: 2,
+		Numbers:   []int{1, 2, 3, 4},
+		Nested:    [][]int{{1, 2}, {3}},
+		Members:   []*Member{{Name: "Ann", Title: "Dr"}, {Name: "Bob", Title: "Mr"}},
+	})
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"RootMethod", "#root.getValue()", "root value"},
+		{"RootProperty", "#root.threshold", "2"},
+		{"ThisIsRootAtTopLevel", "#this.threshold", "2"},
+		{"ThisInSelection", "numbers.?[#this > 2]", "[3 4]"},
+		{"RootInSelection", "numbers.?[#this > #root.threshold]", "[3 4]"},
+		{"NestedThis", "nested.![#this.![#this * 10]]", "[[10 20] [30]]"},
+		{"NestedRoot", "nested.![#this.?[#this >= #root.threshold]]", "[[2] [3]]"},
+		{"MethodArgumentsUseElementScope", "members.![greet(title)]", "[Hello Dr Hello Mr]"},
+		{"RootInMethodArgument", "members.![greet(#root.getValue())]", "[Hello root value Hello root value]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if fmt.Sprintf("%v", result) != tc.expected {
+				t.Errorf("Expression '%s': expected %s, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
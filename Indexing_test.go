@@ -0,0 +1,184 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/IndexingTests.java
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// newIndexingContext creates a context holding the collections used by the tests
+func newIndexingContext() *ast.StandardEvaluationContext {
+	context := ast.NewStandardEvaluationContextWithRoot(newTestUser())
+	context.SetVariables(map[string]interface{}{
+		"list":    []string{"a", "b", "c"},
+		"array":   [3]int{10, 20, 30},
+		"matrix":  [][]int{{1, 2}, {3, 4}},
+		"map":     map[string]int{"a": 1, "b": 2},
+		"intMap":  map[int]string{1: "one", 2: "two"},
+		"user":    newTestUser(),
+		"i":       2,
+		"word":    "héllo",
+		"number":  42,
+		"pointer": &[]string{"x", "y"},
+		"anyMap":  map[interface{}]int{"a": 1},
+	})
+	return context
+}
+
+// TestIndexing tests indexing into slices, arrays, maps, strings and structs
+func TestIndexing(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newIndexingContext()
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"Slice", "#list[1]", "b"},
+		{"SliceVariableIndex", "#list[#i]", "c"},
+		{"SliceStringIndex", "#list['0']", "a"},
+		{"PointerToSlice", "#pointer[1]", "y"},
+		{"Array", "#array[2]", 30},
+		{"InlineList", "{1,2,3}[1]", "2"},
+		{"Nested", "#matrix[1][0]", 3},
+		{"MapStringKey", "#map['b']", 2},
+		{"MapBareKey", "#map[a]", 1},
+		{"MapMissingKey", "#map['z']", nil},
+		{"MapConvertedKey", "#intMap['2']", "two"},
+		{"String", "'abc'[1]", "b"},
+		{"StringUnicode", "#word[1]", "é"},
+		{"StructField", "#user['name']", "Nikola Tesla"},
+		{"StructNestedMap", "#user['settings']['theme']", "dark"},
+		{"PropertyThenIndex", "settings['theme']", "dark"},
+		{"NullSafe", "#unknown?.[0]", nil},
+		{"NullSafeNonNull", "#list?.[0]", "a"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if fmt.Sprintf("%v", result) != fmt.Sprintf("%v", tc.expected) {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestIndexOutOfBounds tests the typed error returned for invalid indexes
+func TestIndexOutOfBounds(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newIndexingContext()
+
+	testCases := []struct {
+		name       string
+		expression string
+		target     ast.IndexTarget
+		index      int
+		size       int
+		position   int
+	}{
+		{"Slice", "#list[3]", ast.IndexTargetCollection, 3, 3, 5},
+		{"Negative", "#list[-1]", ast.IndexTargetCollection, -1, 3, 5},
+		{"Array", "#array[5]", ast.IndexTargetCollection, 5, 3, 6},
+		{"String", "'abc'[10]", ast.IndexTargetString, 10, 3, 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			var indexErr *ast.IndexOutOfBoundsError
+			if !errors.As(err, &indexErr) {
+				t.Fatalf("Expected IndexOutOfBoundsError for '%s', got %v", tc.expression, err)
+			}
+			if indexErr.Target != tc.target || indexErr.Index != tc.index || indexErr.Size != tc.size || indexErr.Position != tc.position {
+				t.Errorf("Expected %s index %d of %d at position %d, got %+v", tc.target, tc.index, tc.size, tc.position, indexErr)
+			}
+		})
+	}
+}
+
+// TestUnhashableMapKey tests that a key that cannot be hashed is an evaluation
+// error rather than a panic
+func TestUnhashableMapKey(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newIndexingContext()
+
+	testCases := []struct {
+		expression string
+		position   int
+		set        bool
+	}{
+		{"{a:1}[{1,2}]", 5, false},
+		{"{'a':1}[{1}]", 7, false},
+		{"#anyMap[{1}]", 7, false},
+		{"#anyMap[{1}]", 7, true},
+		{"{{1}:2}", 1, false},
+		{"{'a':1, {'b':2}:3}", 8, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/set=%v", tc.expression, tc.set), func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			if tc.set {
+				err = expr.SetValue(context, nil, 2)
+			} else {
+				_, err = expr.GetValueWithContext(context)
+			}
+			var evalErr *ast.SpelEvaluationException
+			if !errors.As(err, &evalErr) || evalErr.Message != ast.UNHASHABLE_MAP_KEY || evalErr.Position != tc.position {
+				t.Errorf("Expression '%s': expected UNHASHABLE_MAP_KEY at position %d, got %v", tc.expression, tc.position, err)
+			}
+		})
+	}
+}
+
+// TestIndexingErrors tests indexing failures other than out of bounds
+func TestIndexingErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newIndexingContext()
+
+	errorCases := []struct {
+		name       string
+		expression string
+	}{
+		{"IndexIntoNull", "#unknown[0]"},
+		{"UnsupportedType", "#number[0]"},
+		{"NonNumericIndex", "#list['x']"},
+		{"UnknownField", "#user['wibble']"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			if _, err := expr.GetValueWithContext(context); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"reflect"
 	"testing"
 )
 
@@ -468,3 +469,164 @@ func TestMethodInvocationSpecialCases(t *testing.T) {
 		}
 	})
 }
+
+// PlaceOfBirth is the nested object returned by Inventor.GetPlaceOfBirth
+type PlaceOfBirth struct {
+	city string
+}
+
+func (p *PlaceOfBirth) GetCity() string {
+	return p.city
+}
+
+// Inventor mirrors the Spring test fixture used by the method invocation tests
+type Inventor struct {
+	Name         string
+	placeOfBirth *PlaceOfBirth
+}
+
+func (i *Inventor) GetPlaceOfBirth() *PlaceOfBirth {
+	return i.placeOfBirth
+}
+
+func (i *Inventor) JoinThreeStrings(a, b, c string) string {
+	return a + b + c
+}
+
+func (i *Inventor) PrintDouble(d float64) string {
+	return fmt.Sprintf("%.2f", d)
+}
+
+func (i *Inventor) TimesTwo(n int) int {
+	return n * 2
+}
+
+func (i *Inventor) AVarargsMethod(strings ...string) string {
+	return fmt.Sprint(strings)
+}
+
+func (i *Inventor) AVarargsMethod2(n int, strings ...string) string {
+	return fmt.Sprintf("%d-%v", n, strings)
+}
+
+func (i *Inventor) NullableArgument(other *Inventor) string {
+	if other == nil {
+		return "null"
+	}
+	return other.Name
+}
+
+func (i *Inventor) ThrowException(code int) (string, error) {
+	if code != 0 {
+		return "", fmt.Errorf("exception %d", code)
+	}
+	return "ok", nil
+}
+
+func (i *Inventor) Panic() string {
+	panic("boom")
+}
+
+// TestMethodInvocationEvaluation 测试方法调用的求值
+func TestMethodInvocationEvaluation(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContextWithRoot(&Inventor{
+		Name:         "Nikola Tesla",
+		placeOfBirth: &PlaceOfBirth{city: "Smiljan"},
+	})
+	context.SetVariable("strings", []string{"x", "y"})
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"ChainedMethods", "getPlaceOfBirth().getCity()", "Smiljan"},
+		{"MethodThenProperty", "getPlaceOfBirth().city", "Smiljan"},
+		{"StringArguments", "joinThreeStrings('a', 'b', 'c')", "abc"},
+		{"ConvertedArgument", "printDouble(3)", "3.00"},
+		{"StringToNumberArgument", "printDouble('2.5')", "2.50"},
+		{"WholeRealToIntArgument", "timesTwo(2.0)", 4},
+		{"NumberToStringArgument", "joinThreeStrings(1, 2, 3)", "123"},
+		{"NullArgument", "nullableArgument(null)", "null"},
+		{"VarargsNone", "aVarargsMethod()", "[]"},
+		{"VarargsOne", "aVarargsMethod('a')", "[a]"},
+		{"VarargsMany", "aVarargsMethod('a', 'b', 'c')", "[a b c]"},
+		{"VarargsConverted", "aVarargsMethod(1)", "[1]"},
+		{"VarargsSlice", "aVarargsMethod(#strings)", "[x y]"},
+		{"VarargsAfterFixed", "aVarargsMethod2(5, 'a', 'b')", "5-[a b]"},
+		{"ErrorResultNil", "throwException(0)", "ok"},
+		{"NullSafeOnNull", "#unknown?.getName()", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("解析表达式失败 '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("求值失败 '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("表达式 '%s': 期望 %v, 实际 %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestMethodInvocationErrors 测试方法调用求值失败的情况
+func TestMethodInvocationErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContextWithRoot(&Inventor{Name: "Nikola Tesla"})
+
+	errorCases := []struct {
+		name       string
+		expression string
+	}{
+		{"UnknownMethod", "wibble()"},
+		{"WrongArgumentCount", "joinThreeStrings('a')"},
+		{"UnconvertibleArgument", "printDouble('abc')"},
+		{"LossyRealToIntArgument", "timesTwo(2.7)"},
+		{"NullForValueParameter", "printDouble(null)"},
+		{"ErrorResult", "throwException(1)"},
+		{"PanicInMethod", "panic()"},
+		{"MethodOnNull", "#unknown.getName()"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("解析表达式失败 '%s': %v", tc.expression, err)
+			}
+
+			if _, err := expr.GetValueWithContext(context); err == nil {
+				t.Errorf("期望表达式 '%s' 求值失败, 但没有错误", tc.expression)
+			}
+		})
+	}
+
+	// A panicking type converter is reported as an error
+	context.SetTypeConverter(panickingConverter{})
+	expr, err := parser.ParseExpression("printDouble('2.5')")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+	if _, err := expr.GetValueWithContext(context); err == nil {
+		t.Errorf("期望转换器的 panic 作为错误返回, 但没有错误")
+	}
+}
+
+// panickingConverter is a TypeConverter that panics on every conversion
+type panickingConverter struct{}
+
+func (panickingConverter) CanConvert(sourceType, targetType reflect.Type) bool {
+	return true
+}
+
+func (panickingConverter) ConvertValue(value interface{}, targetType reflect.Type) (interface{}, error) {
+	panic("converter failure")
+}
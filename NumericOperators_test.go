@@ -0,0 +1,345 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/OperatorTests.java
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+func newNumericContext() *ast.StandardEvaluationContext {
+	context := ast.NewStandardEvaluationContext()
+	context.SetVariable("big", big.NewInt(3))
+	context.SetVariable("decimal", big.NewFloat(2.5))
+	context.SetVariable("nan", math.NaN())
+	context.SetVariable("count", 10)
+	context.SetVariable("small", int16(7))
+	return context
+}
+
+// TestNumericPromotion tests literal types, binary numeric promotion and Java
+// integer semantics. Results must have the exact type Java would produce.
+func TestNumericPromotion(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newNumericContext()
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		// Literals
+		{"IntLiteral", "42", int32(42)},
+		{"LongLiteral", "42L", int64(42)},
+		{"HexLiteral", "0xFF", int32(255)},
+		{"DoubleLiteral", "4.2", 4.2},
+		{"FloatLiteral", "4.5f", float32(4.5)},
+
+		// Promotion
+		{"IntPlusInt", "1 + 2", int32(3)},
+		{"IntPlusLong", "1 + 2L", int64(3)},
+		{"IntPlusFloat", "1 + 1.5f", float32(2.5)},
+		{"FloatPlusDouble", "1.5f + 1.5", 3.0},
+		{"LongTimesDouble", "2L * 1.5", 3.0},
+		{"ShortPlusInt", "#small + 1", int32(8)},
+		{"GoIntPlusInt", "#count + 1", 11},
+		{"GoIntPlusLong", "#count + 1L", int64(11)},
+
+		// Integer division and remainder
+		{"IntDivision", "7 / 2", int32(3)},
+		{"NegativeIntDivision", "-7 / 2", int32(-3)},
+		{"LongDivision", "7L / 2", int64(3)},
+		{"DoubleDivision", "7.0 / 2", 3.5},
+		{"FloatDivision", "3.0f / 2", float32(1.5)},
+		{"Remainder", "7 % 3", int32(1)},
+		{"NegativeDividendRemainder", "-7 % 3", int32(-1)},
+		{"NegativeDivisorRemainder", "7 % -3", int32(1)},
+		{"DoubleRemainder", "-7.5 % 2", -1.5},
+
+		// Overflow
+		{"IntOverflow", "T(Integer).MAX_VALUE + 1", int32(math.MinInt32)},
+		{"IntMultiplyOverflow", "100000 * 100000", int32(1410065408)},
+		{"LongMultiply", "100000L * 100000", int64(10000000000)},
+		{"LongOverflow", "T(Long).MAX_VALUE + 1", int64(math.MinInt64)},
+		{"NegateMinValue", "-T(Integer).MIN_VALUE", int32(math.MinInt32)},
+		{"NegateShort", "-#small", int32(-7)},
+
+		// Power
+		{"IntPower", "2 ^ 10", int32(1024)},
+		{"IntPowerBeyondInt", "2 ^ 31", int64(2147483648)},
+		{"LongPower", "2L ^ 3", int64(8)},
+		{"DoublePower", "2.0 ^ 2", 4.0},
+		{"FloatPower", "2.0f ^ 2", 4.0},
+		{"NegativeIntPower", "2 ^ -1", int32(0)},
+
+		// Math functions follow the same promotion
+		{"MathMaxLong", "T(Math).max(1, 2L)", int64(2)},
+		{"MathMaxDouble", "T(Math).max(1, 2.5)", 2.5},
+		{"MathAbsMinValue", "T(Math).abs(T(Integer).MIN_VALUE)", int32(math.MinInt32)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v (%T), got %v (%T)", tc.expression, tc.expected, tc.expected, result, result)
+			}
+		})
+	}
+}
+
+// TestBigNumberArithmetic tests *big.Int and *big.Float operands standing in for
+// BigInteger and BigDecimal
+func TestBigNumberArithmetic(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newNumericContext()
+
+	testCases := []struct {
+		name         string
+		expression   string
+		expectedType string
+		expected     string
+	}{
+		{"BigIntegerPlusInt", "#big + 1", "*big.Int", "4"},
+		{"BigIntegerDivision", "#big / 2", "*big.Int", "1"},
+		{"BigIntegerRemainder", "-#big % 2", "*big.Int", "-1"},
+		{"BigIntegerPower", "#big ^ 40", "*big.Int", "12157665459056928801"},
+		{"BigIntegerTimesDouble", "#big * 2.5", "float64", "7.5"},
+		{"BigDecimalTimesInt", "#decimal * 2", "*big.Float", "5"},
+		{"BigDecimalDivision", "#decimal / 0.5", "*big.Float", "5"},
+		{"BigDecimalRemainder", "#decimal % 1", "*big.Float", "0.5"},
+		{"BigDecimalPlusBigInteger", "#decimal + #big", "*big.Float", "5.5"},
+		{"BigDecimalPower", "#decimal ^ 2", "*big.Float", "6.25"},
+		{"BigDecimalIncrement", "++#decimal", "*big.Float", "3.5"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if fmt.Sprintf("%T", result) != tc.expectedType || fmt.Sprint(result) != tc.expected {
+				t.Errorf("Expression '%s': expected %s (%s), got %v (%T)", tc.expression, tc.expected, tc.expectedType, result, result)
+			}
+		})
+	}
+}
+
+// TestNumericComparison tests equality and relational operators across number
+// types, including NaN and big numbers
+func TestNumericComparison(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newNumericContext()
+
+	testCases := []struct {
+		expression string
+		expected   bool
+	}{
+		{"1 == 1L", true},
+		{"1 == 1.0", true},
+		{"1L != 1.0f", false},
+		{"0.1f == 0.1", false},
+		{"#count == 10", true},
+		{"#big == 3", true},
+		{"#decimal == 2.5", true},
+		{"#nan == #nan", false},
+		{"#nan != #nan", true},
+		{"1L < 2", true},
+		{"2.5f > 2", true},
+		{"#big > 2147483647", false},
+		{"#decimal >= #big", false},
+		{"#nan < 1", false},
+		{"#nan >= 1", false},
+		{"'abc' < 'abd'", true},
+		{"false < true", true},
+		{"null < 1", true},
+		{"#count between {1, 10L}", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestStringOperators tests concatenation with Java number formatting and text repetition
+func TestStringOperators(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		expression string
+		expected   string
+	}{
+		{"'x' + 1", "x1"},
+		{"'x' + 1.0", "x1.0"},
+		{"'x' + 1.5f", "x1.5"},
+		{"'x' + 10000000.0", "x1.0E7"},
+		{"'x' + 0.0001", "x1.0E-4"},
+		{"'x' + 1.0 / 0", "xInfinity"},
+		{"2L + 'x'", "2x"},
+		{"'x' + null", "xnull"},
+		{"'ab' * 3", "ababab"},
+		{"'ab' * 0", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValue()
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected '%s', got '%v'", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestNumericOperatorErrors tests operands that Java would reject
+func TestNumericOperatorErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	for _, expression := range []string{
+		"'abc' - 1",
+		"-'abc'",
+		"true * 2",
+		"'a' < 1",
+		"'ab' * -1",
+		"'ab' * 200",
+		"'ab' * 2L",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+			}
+			if _, err := expr.GetValue(); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", expression)
+			}
+		})
+	}
+}
+
+// TestDivisionByZero tests that integer division and remainder by zero fail
+// like Java's ArithmeticException, while floating point division gives IEEE results
+func TestDivisionByZero(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newNumericContext()
+
+	errorCases := []struct {
+		expression string
+		position   int
+	}{
+		{"1 / 0", 2},
+		{"1L / 0", 3},
+		{"7 % 0", 2},
+		{"#count / (#count - 10)", 7},
+		{"2 + 10 / 0", 7},
+		{"#big / 0", 5},
+		{"#big % 0", 5},
+		{"#decimal / 0", 9},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			if !errors.Is(err, ast.ErrDivisionByZero) {
+				t.Fatalf("Expression '%s': expected division by zero error, got %v", tc.expression, err)
+			}
+			var evalErr *ast.SpelEvaluationException
+			if !errors.As(err, &evalErr) || evalErr.Message != ast.DIVISION_BY_ZERO {
+				t.Errorf("Expression '%s': expected DIVISION_BY_ZERO, got %v", tc.expression, err)
+			}
+			if position := fmt.Sprintf("(position %d)", tc.position); !strings.Contains(err.Error(), position) {
+				t.Errorf("Expression '%s': expected error at %s, got %v", tc.expression, position, err)
+			}
+		})
+	}
+
+	testCases := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"1.0 / 0", math.Inf(1)},
+		{"-1.0 / 0", math.Inf(-1)},
+		{"1 / 0.0", math.Inf(1)},
+		{"1.0f / 0", float32(math.Inf(1))},
+		{"1L / 0.0", math.Inf(1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValue()
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v (%T), got %v (%T)", tc.expression, tc.expected, tc.expected, result, result)
+			}
+		})
+	}
+
+	for _, expression := range []string{"0.0 / 0", "1.0 % 0", "5 % 0.0"} {
+		t.Run(expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+			}
+
+			result, err := expr.GetValue()
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", expression, err)
+			}
+			if f, ok := result.(float64); !ok || !math.IsNaN(f) {
+				t.Errorf("Expression '%s': expected NaN, got %v (%T)", expression, result, result)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"strings"
 	"testing"
 )
 
@@ -282,3 +283,136 @@ func TestOperatorOverloaderEvaluation(t *testing.T) {
 		}
 	})
 }
+
+// stringAndBooleanAddition 对应 Spring 测试中的 StringAndBooleanAddition
+type stringAndBooleanAddition struct{}
+
+func (stringAndBooleanAddition) OverridesOperation(operation ast.Operation, left, right interface{}) (bool, error) {
+	if operation != ast.OperationAdd && operation != ast.OperationSubtract {
+		return false, nil
+	}
+	_, isString := left.(string)
+	_, isBool := right.(bool)
+	return isString && isBool, nil
+}
+
+func (stringAndBooleanAddition) Operate(operation ast.Operation, left, right interface{}) (interface{}, error) {
+	if operation == ast.OperationAdd {
+		return fmt.Sprintf("%s%t", left, right), nil
+	}
+	return left, nil
+}
+
+// moneyArithmetic 为 Money 提供加减法和整数乘法
+type moneyArithmetic struct{}
+
+func (moneyArithmetic) OverridesOperation(operation ast.Operation, left, right interface{}) (bool, error) {
+	if _, ok := left.(Money); !ok {
+		return false, nil
+	}
+	switch right.(type) {
+	case int32:
+		return operation == ast.OperationMultiply, nil
+	case Money:
+		return operation == ast.OperationAdd || operation == ast.OperationSubtract, nil
+	}
+	return false, nil
+}
+
+func (moneyArithmetic) Operate(operation ast.Operation, left, right interface{}) (interface{}, error) {
+	money := left.(Money)
+	if operation == ast.OperationMultiply {
+		return Money{Cents: money.Cents * int64(right.(int32)), Currency: money.Currency}, nil
+	}
+	other := right.(Money)
+	if other.Currency != money.Currency {
+		return nil, fmt.Errorf("cannot %s %s and %s", operation, money.Currency, other.Currency)
+	}
+	if operation == ast.OperationAdd {
+		return Money{Cents: money.Cents + other.Cents, Currency: money.Currency}, nil
+	}
+	return Money{Cents: money.Cents - other.Cents, Currency: money.Currency}, nil
+}
+
+// TestOperatorOverloader 测试求值时对不支持的操作数调用操作符重载器
+func TestOperatorOverloader(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	expr, err := parser.ParseExpression("'abc' - true")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if _, err := expr.GetValue(); err == nil || !strings.Contains(err.Error(), "operator '-' is not supported") {
+		t.Errorf("没有重载器时 'abc' - true 应该失败, 实际 %v", err)
+	}
+
+	context := ast.NewStandardEvaluationContext()
+	context.SetOperatorOverloader(stringAndBooleanAddition{})
+	for _, tc := range []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"'abc' + true", "abctrue"},
+		{"'abc' - true", "abc"},
+		{"'abc' + null", "abcnull"},
+		{"1 + 2", int32(3)},
+	} {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("解析 '%s' 失败: %v", tc.expression, err)
+		}
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("求值 '%s' 失败: %v", tc.expression, err)
+		}
+		if result != tc.expected {
+			t.Errorf("表达式 '%s': 期望 %v, 实际 %v", tc.expression, tc.expected, result)
+		}
+	}
+
+	context.SetVariables(map[string]interface{}{
+		"price":  Money{Cents: 250, Currency: "EUR"},
+		"refund": Money{Cents: 100, Currency: "EUR"},
+		"fee":    Money{Cents: 100, Currency: "USD"},
+	})
+	context.SetOperatorOverloader(moneyArithmetic{})
+	for _, tc := range []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"#price * 2", Money{Cents: 500, Currency: "EUR"}},
+		{"#price * 2 - #refund", Money{Cents: 400, Currency: "EUR"}},
+		{"#price + #refund + #refund", Money{Cents: 450, Currency: "EUR"}},
+	} {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("解析 '%s' 失败: %v", tc.expression, err)
+		}
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("求值 '%s' 失败: %v", tc.expression, err)
+		}
+		if result != tc.expected {
+			t.Errorf("表达式 '%s': 期望 %v, 实际 %v", tc.expression, tc.expected, result)
+		}
+	}
+
+	for _, tc := range []struct {
+		expression string
+		message    string
+	}{
+		{"#price - #fee", "cannot - EUR and USD"},
+		{"#price / 2", "operator '/' is not supported between objects of type 'main.Money' and 'int32' (position 0)"},
+		{"1 + #price % #refund", "operator '%' is not supported"},
+		{"#price ^ 2", "operator '^' is not supported"},
+		{"'abc' - true", "operator '-' is not supported"},
+	} {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("解析 '%s' 失败: %v", tc.expression, err)
+		}
+		if _, err := expr.GetValueWithContext(context); err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("表达式 '%s': 期望错误包含 '%s', 实际 %v", tc.expression, tc.message, err)
+		}
+	}
+}
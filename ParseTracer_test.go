@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// recordingTracer keeps the parse events it receives
+type recordingTracer struct {
+	tokens []string
+	nodes  []string
+}
+
+func (r *recordingTracer) TokensProduced(expressionString string, tokens []*ast.Token) {
+	for _, token := range tokens {
+		r.tokens = append(r.tokens, token.Kind.String())
+	}
+}
+
+func (r *recordingTracer) NodeConstructed(expressionString string, node ast.SpelNode) {
+	r.nodes = append(r.nodes, node.ToStringAST())
+}
+
+// TestParseTracer tests that the configured tracer receives the token stream
+// and the nodes, children before their parent
+func TestParseTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	config := ast.NewSpelParserConfiguration()
+	config.Tracer = tracer
+	parser := ast.NewSpelExpressionParserWithConfig(config)
+
+	if _, err := parser.ParseExpression("1 + foo(2)"); err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+
+	expectedTokens := []string{"LITERAL_INT", "PLUS(+)", "IDENTIFIER", "LPAREN(()", "LITERAL_INT", "RPAREN())"}
+	if !reflect.DeepEqual(tracer.tokens, expectedTokens) {
+		t.Errorf("Expected tokens %v, got %v", expectedTokens, tracer.tokens)
+	}
+	expectedNodes := []string{"1", "2", "foo(2)", "(1 + foo(2))"}
+	if !reflect.DeepEqual(tracer.nodes, expectedNodes) {
+		t.Errorf("Expected nodes %v, got %v", expectedNodes, tracer.nodes)
+	}
+
+	// Each expression of a template is traced
+	tracer.tokens, tracer.nodes = nil, nil
+	if _, err := parser.ParseExpressionWithContext("a #{1} b #{2}", ast.NewTemplateParserContext()); err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	if !reflect.DeepEqual(tracer.nodes, []string{"1", "2"}) {
+		t.Errorf("Expected the nodes of both template expressions, got %v", tracer.nodes)
+	}
+
+	// No nodes are reported for an expression that fails to parse
+	tracer.tokens, tracer.nodes = nil, nil
+	if _, err := parser.ParseExpression("1 +"); err == nil {
+		t.Fatalf("Expected parse error, but got none")
+	}
+	if len(tracer.tokens) != 2 || len(tracer.nodes) != 0 {
+		t.Errorf("Expected only the tokens of the failed expression, got %v and %v", tracer.tokens, tracer.nodes)
+	}
+}
+
+// TestSlogParseTracer tests that parse events are logged as structured records
+func TestSlogParseTracer(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	config := ast.NewSpelParserConfiguration()
+	config.Tracer = ast.NewSlogParseTracer(logger)
+
+	if _, err := ast.NewSpelExpressionParserWithConfig(config).ParseExpression("a.b"); err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode log record '%s': %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected a tokens record and 3 node records, got %d: %s", len(records), buffer.String())
+	}
+
+	if records[0]["msg"] != "tokens produced" || records[0]["count"] != 3.0 || records[0]["expression"] != "a.b" {
+		t.Errorf("Unexpected tokens record: %v", records[0])
+	}
+	last := records[3]
+	if last["msg"] != "node constructed" || last["type"] != "CompoundExpression" || last["start"] != 0.0 || last["end"] != 3.0 {
+		t.Errorf("Unexpected node record: %v", last)
+	}
+}
+
+// TestSlogParseTracerDisabled tests that nothing is built for the records of a
+// logger that is not enabled for debug
+func TestSlogParseTracerDisabled(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	tracer := ast.NewSlogParseTracer(logger)
+	expr, err := ast.NewSpelExpressionParser().ParseExpression("a.b + c[1]")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	tokens := []*ast.Token{ast.NewTokenWithData(ast.IDENTIFIER, []rune("a"), 0, 1)}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		tracer.TokensProduced("a.b + c[1]", tokens)
+		tracer.NodeConstructed("a.b + c[1]", expr.AST)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations with debug disabled, got %v", allocs)
+	}
+}
+
+// TestDoParseExpressionIsSilent tests that parsing does not write to stdout
+func TestDoParseExpressionIsSilent(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	_, parseErr := ast.NewSpelExpressionParser().DoParseExpression("1 + 2")
+	os.Stdout = stdout
+	writer.Close()
+
+	output, _ := io.ReadAll(reader)
+	if parseErr != nil {
+		t.Fatalf("Failed to parse expression: %v", parseErr)
+	}
+	if len(output) != 0 {
+		t.Errorf("Expected no output, got: %s", output)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// TestParserLimits tests that each limit of the configuration fails with its
+// own message code
+func TestParserLimits(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configure  func(config *ast.SpelParserConfiguration)
+		expression string
+		message    ast.SpelMessage
+		position   int
+	}{
+		{"NestingDepth", func(c *ast.SpelParserConfiguration) { c.MaxNestingDepth = 2 }, "((1))", ast.MAX_NESTING_DEPTH_EXCEEDED, 2},
+		{"NestingDepthArguments", func(c *ast.SpelParserConfiguration) { c.MaxNestingDepth = 3 }, "f(g(h(1)))", ast.MAX_NESTING_DEPTH_EXCEEDED, 6},
+		{"NestingDepthUnary", func(c *ast.SpelParserConfiguration) { c.MaxNestingDepth = 3 }, "- - - 1", ast.MAX_NESTING_DEPTH_EXCEEDED, 6},
+		{"NodeCount", func(c *ast.SpelParserConfiguration) { c.MaxNodeCount = 4 }, "{1, 2, 3, 4}", ast.MAX_NODE_COUNT_EXCEEDED, 10},
+		{"TokenCount", func(c *ast.SpelParserConfiguration) { c.MaxTokenCount = 3 }, "1 + 2 + 3", ast.MAX_TOKEN_COUNT_EXCEEDED, 6},
+		{"StringLiteralLength", func(c *ast.SpelParserConfiguration) { c.MaxStringLiteralLength = 3 }, "'ab' + 'abcd'", ast.MAX_STRING_LITERAL_LENGTH_EXCEEDED, 7},
+		{"DoubleQuotedStringLiteralLength", func(c *ast.SpelParserConfiguration) { c.MaxStringLiteralLength = 3 }, "\"abcd\"", ast.MAX_STRING_LITERAL_LENGTH_EXCEEDED, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := ast.NewSpelParserConfiguration()
+			tc.configure(config)
+			parser := ast.NewSpelExpressionParserWithConfig(config)
+
+			_, err := parser.ParseExpression(tc.expression)
+			var parseErr *ast.SpelParseException
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expression '%s': expected a SpelParseException, got %v", tc.expression, err)
+			}
+			if parseErr.Message != tc.message || parseErr.Position != tc.position {
+				t.Errorf("Expression '%s': expected %v at position %d, got %v at position %d (%v)",
+					tc.expression, tc.message, tc.position, parseErr.Message, parseErr.Position, err)
+			}
+
+			// The same expression parses within the default limits
+			if _, err := ast.NewSpelExpressionParser().ParseExpression(tc.expression); err != nil {
+				t.Errorf("Expression '%s': expected no error with the default limits, got %v", tc.expression, err)
+			}
+		})
+	}
+}
+
+// TestParserLimitsDisabled tests that a zero limit is not enforced
+func TestParserLimitsDisabled(t *testing.T) {
+	config := ast.NewSpelParserConfiguration()
+	config.MaxNestingDepth = 0
+	config.MaxNodeCount = 0
+	config.MaxTokenCount = 0
+	config.MaxStringLiteralLength = 0
+	config.MaximumExpressionLength = 0
+	expression := strings.Repeat("(", 300) + "'" + strings.Repeat("a", 20000) + "'" + strings.Repeat(")", 300)
+
+	expr, err := ast.NewSpelExpressionParserWithConfig(config).ParseExpression(expression)
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	if value, _ := expr.GetValue(); value != strings.Repeat("a", 20000) {
+		t.Errorf("Unexpected value of length %d", len(value.(string)))
+	}
+}
+
+// TestPathologicalExpressions tests that hostile input fails with a limit
+// error instead of exhausting the stack, also when the expression length is
+// not limited
+func TestPathologicalExpressions(t *testing.T) {
+	const n = 100000
+
+	testCases := []struct {
+		name       string
+		expression string
+		message    ast.SpelMessage
+	}{
+		{"Parentheses", strings.Repeat("(", n) + "1" + strings.Repeat(")", n), ast.MAX_TOKEN_COUNT_EXCEEDED},
+		{"InlineLists", strings.Repeat("{", n) + strings.Repeat("}", n), ast.MAX_TOKEN_COUNT_EXCEEDED},
+		{"StringLiteral", "'" + strings.Repeat("a", n) + "'", ast.MAX_STRING_LITERAL_LENGTH_EXCEEDED},
+		{"Sum", strings.Repeat("1+", n) + "1", ast.MAX_TOKEN_COUNT_EXCEEDED},
+	}
+
+	unlimitedTokens := []struct {
+		name       string
+		expression string
+		message    ast.SpelMessage
+	}{
+		{"Parentheses", strings.Repeat("(", n) + "1" + strings.Repeat(")", n), ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"InlineLists", strings.Repeat("{", n) + strings.Repeat("}", n), ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"InlineArrays", strings.Repeat("[", n) + strings.Repeat("]", n), ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"Indexers", "a" + strings.Repeat("[", n) + "1" + strings.Repeat("]", n), ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"MethodCalls", strings.Repeat("f(", n) + strings.Repeat(")", n), ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"UnclosedMethodCalls", strings.Repeat("f(", n), ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"Negations", strings.Repeat("!", n) + "true", ast.MAX_NESTING_DEPTH_EXCEEDED},
+		{"Sum", strings.Repeat("1+", n) + "1", ast.MAX_NODE_COUNT_EXCEEDED},
+	}
+
+	run := func(t *testing.T, config *ast.SpelParserConfiguration, expression string, message ast.SpelMessage) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Parsing panicked: %v", r)
+			}
+		}()
+		parser := ast.NewSpelExpressionParserWithConfig(config)
+
+		_, err := parser.ParseExpression(expression)
+		var parseErr *ast.SpelParseException
+		if !errors.As(err, &parseErr) || parseErr.Message != message {
+			t.Fatalf("Expected %v, got %v", message, err)
+		}
+
+		if _, diagnostics := parser.ParseWithDiagnostics(expression); len(diagnostics) == 0 || diagnostics[0].Message != message {
+			t.Errorf("Expected the diagnostic %v, got %v", message, diagnostics)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := ast.NewSpelParserConfiguration()
+			config.MaximumExpressionLength = len(tc.expression)
+			run(t, config, tc.expression, tc.message)
+		})
+	}
+	for _, tc := range unlimitedTokens {
+		t.Run("UnlimitedTokens/"+tc.name, func(t *testing.T) {
+			config := ast.NewSpelParserConfiguration()
+			config.MaximumExpressionLength = len(tc.expression)
+			config.MaxTokenCount = 0
+			run(t, config, tc.expression, tc.message)
+		})
+	}
+}
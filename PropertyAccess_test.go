@@ -0,0 +1,214 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/PropertyAccessTests.java
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// Address is a nested property holder used by the property access tests
+type Address struct {
+	City string
+}
+
+// User exposes fields, getters and a map for the property access tests
+type User struct {
+	Name     string
+	Age      int
+	Address  *Address
+	Settings map[string]string
+	active   bool
+	nickname string
+}
+
+func (u *User) IsActive() bool {
+	return u.active
+}
+
+func (u User) GetNickname() string {
+	return u.nickname
+}
+
+func (u *User) Initials() (string, error) {
+	if u.Name == "" {
+		return "", fmt.Errorf("no name")
+	}
+	return u.Name[:1], nil
+}
+
+func newTestUser() *User {
+	return &User{
+		Name:     "Nikola Tesla",
+		Age:      86,
+		Address:  &Address{City: "Smiljan"},
+		Settings: map[string]string{"theme": "dark"},
+		active:   true,
+		nickname: "Nik",
+	}
+}
+
+// TestPropertyAccess tests property and field resolution against Go values
+func TestPropertyAccess(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	user := newTestUser()
+
+	testCases := []struct {
+		name       string
+		expression string
+		root       interface{}
+		expected   interface{}
+	}{
+		{"ExportedField", "name", user, "Nikola Tesla"},
+		{"CaseInsensitiveField", "NAME", user, "Nikola Tesla"},
+		{"NestedField", "address.city", user, "Smiljan"},
+		{"IsGetter", "active", user, true},
+		{"GetGetterOnValue", "nickname", *user, "Nik"},
+		{"GetGetterOnPointer", "nickname", user, "Nik"},
+		{"PlainGetter", "initials", user, "N"},
+		{"MapKey", "settings.theme", user, "dark"},
+		{"MapRoot", "theme", map[string]interface{}{"theme": "light"}, "light"},
+		{"MapRootCaseInsensitive", "Theme", map[string]interface{}{"theme": "light"}, "light"},
+		{"InterfaceWrapped", "city", interface{}(&Address{City: "Paris"}), "Paris"},
+		{"NullSafeOnNil", "address?.city", &User{}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithRoot(tc.root)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestPropertyAccessErrors tests failures when a property cannot be resolved
+func TestPropertyAccessErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	errorCases := []struct {
+		name       string
+		expression string
+		root       interface{}
+	}{
+		{"UnknownProperty", "wibble", newTestUser()},
+		{"UnexportedField", "nickname", &Address{}},
+		{"PropertyOnNull", "address.city", &User{}},
+		{"PropertyOnNilRoot", "name", nil},
+		{"GetterError", "initials", &User{}},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			if _, err := expr.GetValueWithRoot(tc.root); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
+
+// Fragile has a getter and a setter that panic
+type Fragile struct{}
+
+func (f *Fragile) GetBroken() string {
+	panic("broken getter")
+}
+
+func (f *Fragile) SetBroken(value string) {
+	panic("broken setter")
+}
+
+// TestPropertyAccessorPanics tests that a panicking getter or setter fails the
+// evaluation with an exception at the property instead of crashing
+func TestPropertyAccessorPanics(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContextWithRoot(&Fragile{})
+	context.SetVariable("user", &User{})
+
+	testCases := []struct {
+		expression string
+		message    ast.SpelMessage
+		position   int
+	}{
+		{"broken", ast.EXCEPTION_DURING_PROPERTY_READ, 0},
+		{"broken = 'x'", ast.EXCEPTION_DURING_PROPERTY_WRITE, 0},
+		{"#root['broken']", ast.EXCEPTION_DURING_PROPERTY_READ, 5},
+		{"#root['broken'] = 'x'", ast.EXCEPTION_DURING_PROPERTY_WRITE, 5},
+		{"#user.initials", ast.EXCEPTION_DURING_PROPERTY_READ, 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			var evalErr *ast.SpelEvaluationException
+			if !errors.As(err, &evalErr) || evalErr.Message != tc.message || evalErr.Position != tc.position {
+				t.Errorf("Expression '%s': expected %v at position %d, got %v", tc.expression, tc.message, tc.position, err)
+			}
+		})
+	}
+}
+
+// TestCustomPropertyAccessor tests that registered accessors are consulted first
+func TestCustomPropertyAccessor(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := ast.NewStandardEvaluationContextWithRoot(newTestUser())
+	context.AddPropertyAccessor(&constantPropertyAccessor{name: "name", value: "overridden"})
+
+	expr, err := parser.ParseExpression("name")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+
+	result, err := expr.GetValueWithContext(context)
+	if err != nil {
+		t.Fatalf("Failed to evaluate expression: %v", err)
+	}
+	if result != "overridden" {
+		t.Errorf("Expected 'overridden', got %v", result)
+	}
+}
+
+// constantPropertyAccessor answers a single property name with a fixed value
+type constantPropertyAccessor struct {
+	name  string
+	value interface{}
+}
+
+func (a *constantPropertyAccessor) CanRead(context ast.EvaluationContext, target interface{}, name string) bool {
+	return name == a.name
+}
+
+func (a *constantPropertyAccessor) Read(context ast.EvaluationContext, target interface{}, name string) (interface{}, error) {
+	return a.value, nil
+}
+
+func (a *constantPropertyAccessor) CanWrite(context ast.EvaluationContext, target interface{}, name string) bool {
+	return false
+}
+
+func (a *constantPropertyAccessor) Write(context ast.EvaluationContext, target interface{}, name string, newValue interface{}) error {
+	return fmt.Errorf("property '%s' is read-only", name)
+}
@@ -0,0 +1,211 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/SelectionAndProjectionTests.java
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// newSelectionContext creates a context holding the collections used by the tests
+func newSelectionContext() *ast.StandardEvaluationContext {
+	context := ast.NewStandardEvaluationContext()
+	context.SetVariables(map[string]interface{}{
+		"integers": []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		"array":    [5]int{0, 1, 2, 3, 4},
+		"colors":   map[string]int{"red": 1, "green": 2, "blue": 3, "black": 0},
+		"users":    []*User{{Name: "Ann", Age: 30}, {Name: "Bob", Age: 17}, {Name: "Cid", Age: 45}},
+	})
+	return context
+}
+
+// TestSelection tests ?[], ^[] and $[] over slices, arrays and maps
+func TestSelection(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newSelectionContext()
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"InlineListBetween", "{1,2,3,4,5,6}?.?[#this between {2, 4}]", []interface{}{int32(2), int32(3), int32(4)}},
+		{"SelectionWithSlice", "#integers.?[#this < 5]", []int{0, 1, 2, 3, 4}},
+		{"SelectFirstItemInSlice", "#integers.^[#this < 5]", 0},
+		{"SelectLastItemInSlice", "#integers.$[#this < 5]", 4},
+		{"SelectFirstNoMatch", "#integers.^[#this > 100]", nil},
+		{"SelectionWithArray", "#array.?[#this > 2]", []int{3, 4}},
+		{"SelectionNoMatch", "#integers.?[#this > 100]", []int{}},
+		{"SelectionOnEmptyInlineList", "{}.?[true]", []interface{}{}},
+		{"SelectionWithMap", "#colors.?[value > 1]", map[string]int{"green": 2, "blue": 3}},
+		{"SelectionOnMapKey", "#colors.?[key == 'black' or key == 'red']", map[string]int{"black": 0, "red": 1}},
+		{"SelectFirstItemInMap", "#colors.^[value > 0]", map[string]int{"blue": 3}},
+		{"SelectLastItemInMap", "#colors.$[value > 0]", map[string]int{"red": 1}},
+		{"NullSafeSelection", "#unknown?.?[#this > 1]", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expression '%s': expected %v (%T), got %v (%T)", tc.expression, tc.expected, tc.expected, result, result)
+			}
+		})
+	}
+}
+
+// TestSelectionOnStructs tests selection criteria that read element properties
+func TestSelectionOnStructs(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newSelectionContext()
+
+	expr, err := parser.ParseExpression("#users.?[age > 18]")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	result, err := expr.GetValueWithContext(context)
+	if err != nil {
+		t.Fatalf("Failed to evaluate expression: %v", err)
+	}
+
+	adults, ok := result.([]*User)
+	if !ok || len(adults) != 2 || adults[0].Name != "Ann" || adults[1].Name != "Cid" {
+		t.Errorf("Expected Ann and Cid, got %v", result)
+	}
+}
+
+// TestSelectionErrors tests selection over unsupported input and non-boolean criteria
+func TestSelectionErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newSelectionContext()
+	context.SetVariable("number", 42)
+
+	errorCases := []struct {
+		name          string
+		expression    string
+		expectedError string
+	}{
+		{"NonBooleanCriteria", "#integers.?[#this + 1]", "result of selection criteria is not boolean"},
+		{"NonBooleanCriteriaOnMap", "#colors.?[value]", "result of selection criteria is not boolean: int (position 7)"},
+		{"SelectionOnNull", "#unknown.?[#this > 1]", "cannot perform selection on input data of type 'null'"},
+		{"SelectionOnNumber", "#number.?[#this > 1]", "cannot perform selection on input data of type 'int'"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			if err == nil {
+				t.Fatalf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%v'", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+// TestProjection tests ![] over slices, arrays and maps
+func TestProjection(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newSelectionContext()
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"ProjectionWithInlineList", "{1,2,3}.![#this * 2]", "[2 4 6]"},
+		{"ProjectionWithSlice", "#integers.![#this > 7]", "[false false false false false false false false true true]"},
+		{"ProjectionWithArray", "#array.![#this + 1]", "[1 2 3 4 5]"},
+		{"ProjectionOnProperties", "#users.![name]", "[Ann Bob Cid]"},
+		{"ProjectionAfterSelection", "#users.?[age > 18].![name]", "[Ann Cid]"},
+		{"ProjectionWithMapKeys", "#colors.![key]", "[black blue green red]"},
+		{"ProjectionWithMapEntries", "#colors.![key + '=' + value]", "[black=0 blue=3 green=2 red=1]"},
+		{"NullSafeProjection", "#unknown?.![name]", "<nil>"},
+		{"NullSafeProjectionNonNull", "#users?.![age]", "[30 17 45]"},
+		{"ProjectionOnEmptyInlineList", "{}.![1]", "[]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if fmt.Sprintf("%v", result) != tc.expected {
+				t.Errorf("Expression '%s': expected %s, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestProjectionToStringAST tests that the null-safe form of a projection is kept
+func TestProjectionToStringAST(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	for _, expression := range []string{"#list.![name]", "#list?.![name]", "#list?.![#this?.![name]]"} {
+		expr, err := parser.ParseExpression(expression)
+		if err != nil {
+			t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+		}
+		if actual := expr.ToStringAST(); actual != expression {
+			t.Errorf("Expected '%s' to render as itself, got '%s'", expression, actual)
+		}
+	}
+}
+
+// TestProjectionErrors tests projection over unsupported input
+func TestProjectionErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newSelectionContext()
+	context.SetVariable("number", 42)
+
+	errorCases := []struct {
+		name          string
+		expression    string
+		expectedError string
+	}{
+		{"ProjectionOnNull", "#unknown.![name]", "projection is not supported on the type 'null' (position 8)"},
+		{"ProjectionOnNumber", "#number.![#this]", "projection is not supported on the type 'int'"},
+		{"ErrorInProjection", "#users.![wibble]", "property or field 'wibble' cannot be found"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			if err == nil {
+				t.Fatalf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%v'", tc.expectedError, err)
+			}
+		})
+	}
+}
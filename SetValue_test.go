@@ -0,0 +1,176 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/SetValueTests.java
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+func (u *User) SetNickname(nickname string) {
+	u.nickname = nickname
+}
+
+// TestAssignment tests that assignment expressions mutate their target
+func TestAssignment(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+		check      func(user *User, context *ast.StandardEvaluationContext) interface{}
+	}{
+		{"Field", "name = 'Nikola'", "Nikola",
+			func(user *User, _ *ast.StandardEvaluationContext) interface{} { return user.Name }},
+		{"ConvertedField", "age = '42'", 42,
+			func(user *User, _ *ast.StandardEvaluationContext) interface{} { return user.Age }},
+		{"NestedField", "address.city = 'Paris'", "Paris",
+			func(user *User, _ *ast.StandardEvaluationContext) interface{} { return user.Address.City }},
+		{"Setter", "nickname = 'Niko'", "Niko",
+			func(user *User, _ *ast.StandardEvaluationContext) interface{} { return user.nickname }},
+		{"MapProperty", "settings.theme = 'light'", "light",
+			func(user *User, _ *ast.StandardEvaluationContext) interface{} { return user.Settings["theme"] }},
+		{"MapIndex", "settings['font'] = 'mono'", "mono",
+			func(user *User, _ *ast.StandardEvaluationContext) interface{} { return user.Settings["font"] }},
+		{"Variable", "#var = 5", int32(5),
+			func(_ *User, context *ast.StandardEvaluationContext) interface{} {
+				return context.LookupVariable("var")
+			}},
+		{"SliceElement", "#list[0] = 'z'", "z",
+			func(_ *User, context *ast.StandardEvaluationContext) interface{} {
+				return context.LookupVariable("list").([]string)[0]
+			}},
+		{"ArrayElementThroughPointer", "#array[1] = 7", 7,
+			func(_ *User, context *ast.StandardEvaluationContext) interface{} {
+				return context.LookupVariable("array").(*[3]int)[1]
+			}},
+		{"StructIndex", "#user['name'] = 'Ann'", "Ann",
+			func(_ *User, context *ast.StandardEvaluationContext) interface{} {
+				return context.LookupVariable("user").(*User).Name
+			}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := newTestUser()
+			context := ast.NewStandardEvaluationContextWithRoot(user)
+			context.SetVariables(map[string]interface{}{
+				"list":  []string{"a", "b"},
+				"array": &[3]int{1, 2, 3},
+				"user":  newTestUser(),
+			})
+
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValueWithContext(context); err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+
+			if actual := tc.check(user, context); !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, actual)
+			}
+		})
+	}
+}
+
+// TestSetValue tests SpelExpression.SetValue and IsWritable
+func TestSetValue(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	t.Run("WithRoot", func(t *testing.T) {
+		user := newTestUser()
+		expr, err := parser.ParseExpression("address.city")
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if !expr.IsWritable(nil, user) {
+			t.Error("Expected address.city to be writable")
+		}
+		if err := expr.SetValue(nil, user, "Vienna"); err != nil {
+			t.Fatalf("Failed to set value: %v", err)
+		}
+		if user.Address.City != "Vienna" {
+			t.Errorf("Expected 'Vienna', got %v", user.Address.City)
+		}
+	})
+
+	t.Run("WithContextRoot", func(t *testing.T) {
+		user := newTestUser()
+		context := ast.NewStandardEvaluationContextWithRoot(user)
+		expr, err := parser.ParseExpression("settings[theme]")
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if err := expr.SetValue(context, nil, "blue"); err != nil {
+			t.Fatalf("Failed to set value: %v", err)
+		}
+		if user.Settings["theme"] != "blue" {
+			t.Errorf("Expected 'blue', got %v", user.Settings["theme"])
+		}
+	})
+
+	t.Run("Variable", func(t *testing.T) {
+		context := ast.NewStandardEvaluationContext()
+		expr, err := parser.ParseExpression("#counter")
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if err := expr.SetValue(context, nil, 3); err != nil {
+			t.Fatalf("Failed to set value: %v", err)
+		}
+		if context.LookupVariable("counter") != 3 {
+			t.Errorf("Expected 3, got %v", context.LookupVariable("counter"))
+		}
+	})
+
+	notWritable := []string{"5", "#this", "#root", "'abc'[0]", "initials()"}
+	for _, expression := range notWritable {
+		t.Run("NotWritable "+expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+			}
+			if expr.IsWritable(nil, newTestUser()) {
+				t.Errorf("Expected '%s' not to be writable", expression)
+			}
+		})
+	}
+}
+
+// TestSetValueErrors tests assignments that cannot be performed
+func TestSetValueErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	errorCases := []struct {
+		name       string
+		expression string
+		root       interface{}
+	}{
+		{"Literal", "5 = 3", newTestUser()},
+		{"Root", "#root = 1", newTestUser()},
+		{"Unconvertible", "age = 'abc'", newTestUser()},
+		{"UnknownProperty", "wibble = 1", newTestUser()},
+		{"StructByValue", "name = 'x'", *newTestUser()},
+		{"PropertyOfNull", "#unknown.name = 'x'", newTestUser()},
+		{"IndexOutOfBounds", "#root[5] = 'x'", []string{"a"}},
+		{"StringIndex", "#root[0] = 'x'", "abc"},
+		{"MethodCall", "initials() = 'x'", newTestUser()},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValueWithRoot(tc.root); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
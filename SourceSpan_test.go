@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// collectSpans returns the span of each node of the tree, keyed by its AST string
+func collectSpans(node ast.SpelNode, spans map[string]string) {
+	spans[node.ToStringAST()] = node.GetSourceSpan().String()
+	for _, child := range node.GetChildren() {
+		if child != nil {
+			collectSpans(child, spans)
+		}
+	}
+}
+
+// TestNodeSourceSpans tests the line and column of the nodes of multi-line expressions
+func TestNodeSourceSpans(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		expression string
+		spans      map[string]string
+	}{
+		{
+			expression: "1 +\n  foo(2,\n bar)",
+			spans: map[string]string{
+				"(1 + foo(2, bar))": "1:1-3:6",
+				"1":                 "1:1-1:2",
+				"foo(2, bar)":       "2:3-3:6",
+				"2":                 "2:7-2:8",
+				"bar":               "3:2-3:5",
+			},
+		},
+		{
+			expression: "a.b[0]\n  .c",
+			spans: map[string]string{
+				"a.b[0].c": "1:1-2:5",
+				"[0]":      "1:4-1:7",
+				".c":       "2:3-2:5",
+			},
+		},
+		{
+			expression: "'ü'\n+ 1",
+			spans: map[string]string{
+				"('ü' + 1)": "1:1-2:4",
+				"'ü'":       "1:1-1:4",
+				"1":         "2:3-2:4",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			spans := make(map[string]string)
+			collectSpans(expr.AST, spans)
+			for node, expected := range tc.spans {
+				if spans[node] != expected {
+					t.Errorf("Expression '%s': expected span %s for %s, got '%s'", tc.expression, expected, node, spans[node])
+				}
+			}
+		})
+	}
+}
+
+// TestTokenSourceSpans tests that the tokenizer sets the span of each token
+func TestTokenSourceSpans(t *testing.T) {
+	tokens, err := ast.NewTokenizer("a\n+ 'bc'").Process()
+	if err != nil {
+		t.Fatalf("Failed to tokenize: %v", err)
+	}
+
+	expected := []string{"1:1-1:2", "2:1-2:2", "2:3-2:7"}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d", len(expected), len(tokens))
+	}
+	for i, token := range tokens {
+		if token.Span.String() != expected[i] {
+			t.Errorf("Token %v: expected span %s, got %s", token, expected[i], token.Span)
+		}
+	}
+}
+
+// TestTemplateSourceSpans tests that the spans and positions of template parts
+// refer to the whole template
+func TestTemplateSourceSpans(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	expr, err := parser.ParseExpressionWithContext("Hi\n#{name} and #{1 + 2}", ast.NewTemplateParserContext())
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	spans := make(map[string]string)
+	collectSpans(expr.AST, spans)
+	expected := map[string]string{
+		"name":    "2:3-2:7",
+		"' and '": "2:8-2:13",
+		"(1 + 2)": "2:15-2:20",
+		"2":       "2:19-2:20",
+	}
+	for node, span := range expected {
+		if spans[node] != span {
+			t.Errorf("Expected span %s for %s, got '%s'", span, node, spans[node])
+		}
+	}
+
+	sum := expr.AST.GetChildren()[3]
+	if sum.GetStartPosition() != 17 || sum.GetEndPosition() != 22 {
+		t.Errorf("Expected positions 17-22 for (1 + 2), got %d-%d", sum.GetStartPosition(), sum.GetEndPosition())
+	}
+}
+
+// TestExceptionSourceSpans tests the line and column of parse and evaluation errors
+func TestExceptionSourceSpans(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	_, err := parser.ParseExpressionWithContext("Hi\n#{name} and #{1 + }", ast.NewTemplateParserContext())
+	var parseErr *ast.SpelParseException
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected a SpelParseException, got %v", err)
+	}
+	if parseErr.Span.Start.String() != "2:17" {
+		t.Errorf("Expected the error at 2:17, got %s", parseErr.Span.Start)
+	}
+	expected := "problem parsing right operand of '+' (position 19, line 2, column 17)"
+	if err.Error() != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, err.Error())
+	}
+
+	evaluationCases := []struct {
+		expression string
+		start      string
+		message    string
+	}{
+		{"1 +\n #nothing[0]", "2:10", "cannot index into a null value (position 13, line 2, column 10)"},
+		{"1 +\n  nope.bar", "2:3", "property or field 'nope' cannot be found on null (position 6, line 2, column 3)"},
+		{"2 +\n  10 / 0", "2:6", "division by zero (position 9, line 2, column 6): / by zero"},
+		{"1 +\n  {1, 2}[5]", "2:9", "the array has '2' elements, index '5' is invalid (position 12, line 2, column 9): index 5 out of range [0:2]"},
+		{"1 +\n  T(Nope)", "2:3", "type cannot be found 'Nope' (position 6, line 2, column 3)"},
+	}
+	for _, tc := range evaluationCases {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+		}
+		_, err = expr.GetValue()
+		var evalErr *ast.SpelEvaluationException
+		if !errors.As(err, &evalErr) {
+			t.Fatalf("Expression '%s': expected a SpelEvaluationException, got %v", tc.expression, err)
+		}
+		if evalErr.Span.Start.String() != tc.start {
+			t.Errorf("Expression '%s': expected the error at %s, got %s", tc.expression, tc.start, evalErr.Span.Start)
+		}
+		if err.Error() != tc.message {
+			t.Errorf("Expression '%s': expected message '%s', got '%s'", tc.expression, tc.message, err.Error())
+		}
+	}
+}
+
+// TestSourceText tests the text between rune offsets, with multi-byte characters
+func TestSourceText(t *testing.T) {
+	source := ast.NewSourceText("ü + 'ä'\n  x")
+
+	testCases := []struct {
+		start, end int
+		expected   string
+	}{
+		{0, 1, "ü"},
+		{4, 7, "'ä'"},
+		{6, 11, "'\n  x"},
+		{-3, 1, "ü"},
+		{10, 99, "x"},
+		{5, 2, ""},
+	}
+	for _, tc := range testCases {
+		if text := source.Text(tc.start, tc.end); text != tc.expected {
+			t.Errorf("Text(%d, %d): expected %q, got %q", tc.start, tc.end, tc.expected, text)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/ParserErrorMessagesTests.java
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// TestParseExceptions tests the message code and position of parse errors
+func TestParseExceptions(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		expression string
+		message    ast.SpelMessage
+		position   int
+	}{
+		{"1 +", ast.RIGHT_OPERAND_PROBLEM, 2},
+		{"true and )", ast.RIGHT_OPERAND_PROBLEM, 5},
+		{"> 3", ast.LEFT_OPERAND_PROBLEM, 0},
+		{"?:1", ast.LEFT_OPERAND_PROBLEM, 0},
+		{"1 2", ast.MORE_INPUT, 2},
+		{")", ast.MORE_INPUT, 0},
+		{"(1", ast.OOD, 2},
+		{"x ? 1", ast.OOD, 5},
+		{"x ? 1 2", ast.NOT_EXPECTED_TOKEN, 6},
+		{"{1, }", ast.NOT_EXPECTED_TOKEN, 4},
+		{"foo(", ast.RUN_OUT_OF_ARGUMENTS, 4},
+		{"name.1", ast.UNEXPECTED_DATA_AFTER_DOT, 4},
+		{"new String", ast.MISSING_CONSTRUCTOR_ARGS, 10},
+		{"new int[]", ast.MISSING_ARRAY_DIMENSION, 0},
+		{"list.?[]", ast.MISSING_SELECTION_EXPRESSION, 5},
+		{"@", ast.INVALID_BEAN_REFERENCE, 0},
+		{"'abc", ast.NON_TERMINATING_QUOTED_STRING, 0},
+		{"\"abc", ast.NON_TERMINATING_DOUBLE_QUOTED_STRING, 0},
+		{"1 | 2", ast.MISSING_CHARACTER, 2},
+		{"3 ~ 4", ast.UNSUPPORTED_CHARACTER, 3},
+		{"2147483648", ast.NOT_AN_INTEGER, 0},
+		{"9223372036854775808L", ast.NOT_A_LONG, 0},
+		{"1 + 1e400", ast.NOT_A_REAL, 4},
+		{"1e40f", ast.NOT_A_REAL, 0},
+		{"1.5L", ast.REAL_CANNOT_BE_LONG, 0},
+		{"'ü' + ", ast.RIGHT_OPERAND_PROBLEM, 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			_, err := parser.ParseExpression(tc.expression)
+			var parseErr *ast.SpelParseException
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expression '%s': expected a SpelParseException, got %v", tc.expression, err)
+			}
+			if parseErr.Message != tc.message || parseErr.Position != tc.position {
+				t.Errorf("Expression '%s': expected %v at position %d, got %v at position %d (%v)",
+					tc.expression, tc.message, tc.position, parseErr.Message, parseErr.Position, err)
+			}
+			if parseErr.ExpressionString != tc.expression {
+				t.Errorf("Expression '%s': expected the expression string, got '%s'", tc.expression, parseErr.ExpressionString)
+			}
+		})
+	}
+}
+
+// TestTemplateParseExceptions tests that errors inside a template refer to the
+// whole template
+func TestTemplateParseExceptions(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		template string
+		message  ast.SpelMessage
+		position int
+	}{
+		{"Hello #{1 +} world", ast.RIGHT_OPERAND_PROBLEM, 10},
+		{"#{1} and #{'a' 'b'}", ast.MORE_INPUT, 15},
+		{"Hello #{name", ast.MISSING_CHARACTER, 6},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.template, func(t *testing.T) {
+			_, err := parser.ParseExpressionWithContext(tc.template, ast.NewTemplateParserContext())
+			var parseErr *ast.SpelParseException
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Template '%s': expected a SpelParseException, got %v", tc.template, err)
+			}
+			if parseErr.Message != tc.message || parseErr.Position != tc.position || parseErr.ExpressionString != tc.template {
+				t.Errorf("Template '%s': expected %v at position %d, got %v at position %d in '%s'",
+					tc.template, tc.message, tc.position, parseErr.Message, parseErr.Position, parseErr.ExpressionString)
+			}
+		})
+	}
+}
+
+// TestEvaluationExceptions tests the message code and position of evaluation errors
+func TestEvaluationExceptions(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		expression string
+		message    ast.SpelMessage
+		position   int
+	}{
+		{"#nothing[0]", ast.CANNOT_INDEX_INTO_NULL_VALUE, 8},
+		{"{1, 2}.?[#this]", ast.RESULT_OF_SELECTION_CRITERIA_IS_NOT_BOOLEAN, 6},
+		{"null.![#this]", ast.PROJECTION_NOT_SUPPORTED_ON_TYPE, 4},
+		{"#missing()", ast.FUNCTION_NOT_DEFINED, 0},
+		{"'a' < 1", ast.NOT_COMPARABLE, 0},
+		{"1 + (true - 1)", ast.OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES, 5},
+		{"1 + -'a'", ast.OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES, 4},
+		{"'ab' * -1", ast.NEGATIVE_REPEATED_TEXT_COUNT, 0},
+		{"1 instanceof 2", ast.INSTANCEOF_OPERATOR_NEEDS_CLASS_OPERAND, 13},
+		{"@service", ast.NO_BEAN_RESOLVER_REGISTERED, 0},
+		{"!#nothing", ast.TYPE_CONVERSION_ERROR, 1},
+		{"nope", ast.PROPERTY_OR_FIELD_NOT_READABLE_ON_NULL, 0},
+		{"'abc'.nope", ast.PROPERTY_OR_FIELD_NOT_READABLE, 5},
+		{"nope = 1", ast.PROPERTY_OR_FIELD_NOT_WRITABLE_ON_NULL, 0},
+		{"'abc'.nope = 1", ast.PROPERTY_OR_FIELD_NOT_WRITABLE, 5},
+		{"nope()", ast.METHOD_CALL_ON_NULL_OBJECT_NOT_ALLOWED, 0},
+		{"'abc'.nope()", ast.METHOD_NOT_FOUND, 5},
+		{"#root = 1", ast.NOT_ASSIGNABLE, 0},
+		{"#root++", ast.OPERAND_NOT_INCREMENTABLE, 0},
+		{"--'abc'", ast.OPERAND_NOT_DECREMENTABLE, 0},
+		{"#nothing--", ast.OPERAND_NOT_DECREMENTABLE, 0},
+		{"{1, 2}[5]", ast.ARRAY_INDEX_OUT_OF_BOUNDS, 6},
+		{"'abc'[10]", ast.STRING_INDEX_OUT_OF_BOUNDS, 5},
+		{"{1, 2}['x']", ast.TYPE_CONVERSION_ERROR, 6},
+		{"'abc' matches '['", ast.INVALID_PATTERN, 14},
+		{"1 between {1}", ast.BETWEEN_RIGHT_OPERAND_MUST_BE_TWO_ELEMENT_LIST, 10},
+		{"T(Nope)", ast.TYPE_NOT_FOUND, 0},
+		{"T(Math[])", ast.ARRAY_ELEMENT_TYPE_NOT_SUPPORTED, 0},
+		{"new Nope()", ast.CONSTRUCTOR_NOT_FOUND, 0},
+		{"new Integer('x')", ast.CONSTRUCTOR_INVOCATION_PROBLEM, 0},
+		{"new int[-1]", ast.NEGATIVE_ARRAY_DIMENSION, 8},
+		{"new int['x']", ast.TYPE_CONVERSION_ERROR, 8},
+		{"new int[2]{1}", ast.INITIALIZER_LENGTH_INCORRECT, 0},
+		{"new int[]{'x'}", ast.TYPE_CONVERSION_ERROR, 9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValue()
+			var evalErr *ast.SpelEvaluationException
+			if !errors.As(err, &evalErr) {
+				t.Fatalf("Expression '%s': expected a SpelEvaluationException, got %v", tc.expression, err)
+			}
+			if evalErr.Message != tc.message || evalErr.Position != tc.position {
+				t.Errorf("Expression '%s': expected %v at position %d, got %v at position %d (%v)",
+					tc.expression, tc.message, tc.position, evalErr.Message, evalErr.Position, err)
+			}
+			if evalErr.ExpressionString != tc.expression {
+				t.Errorf("Expression '%s': expected the expression string, got '%s'", tc.expression, evalErr.ExpressionString)
+			}
+		})
+	}
+
+	// The cause of an exception stays reachable
+	expr, err := parser.ParseExpression("true and null")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	if _, err := expr.GetValue(); !errors.Is(err, ast.ErrCannotConvertToBoolean) {
+		t.Errorf("Expected the boolean conversion error as cause, got %v", err)
+	}
+}
+
+// TestMethodInvocationExceptions tests the message code and position of errors
+// raised while resolving and invoking a method
+func TestMethodInvocationExceptions(t *testing.T) {
+	locator := ast.NewStandardTypeLocator()
+	picker := locator.RegisterType("Picker", nil)
+	for _, function := range []interface{}{
+		func(value int64) int64 { return value },
+		func(value float64) float64 { return value },
+	} {
+		if err := picker.RegisterFunction("pick", function); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := picker.RegisterFunction("fail", func() (string, error) { return "", errors.New("failed") }); err != nil {
+		t.Fatal(err)
+	}
+	context := ast.NewStandardEvaluationContext()
+	context.SetTypeLocator(locator)
+	parser := ast.NewSpelExpressionParser()
+
+	testCases := []struct {
+		expression string
+		message    ast.SpelMessage
+		position   int
+	}{
+		{"T(Picker).pick('1')", ast.MULTIPLE_POSSIBLE_METHODS, 9},
+		{"T(Picker).fail()", ast.EXCEPTION_DURING_METHOD_INVOCATION, 9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			var evalErr *ast.SpelEvaluationException
+			if !errors.As(err, &evalErr) {
+				t.Fatalf("Expression '%s': expected a SpelEvaluationException, got %v", tc.expression, err)
+			}
+			if evalErr.Message != tc.message || evalErr.Position != tc.position {
+				t.Errorf("Expression '%s': expected %v at position %d, got %v at position %d (%v)",
+					tc.expression, tc.message, tc.position, evalErr.Message, evalErr.Position, err)
+			}
+		})
+	}
+}
+
+// TestFormatWithCaret tests rendering the expression with a marker under the
+// error position
+func TestFormatWithCaret(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      interface{ FormatWithCaret() string }
+		expected string
+	}{
+		{
+			name:     "ParseException",
+			err:      ast.NewSpelParseException("1 + * 2", 4, ast.RIGHT_OPERAND_PROBLEM, "+"),
+			expected: "problem parsing right operand of '+' (position 4)\n1 + * 2\n    ^",
+		},
+		{
+			name:     "EndOfExpression",
+			err:      ast.NewSpelParseException("(1", 2, ast.OOD),
+			expected: "unexpectedly ran out of input (position 2)\n(1\n  ^",
+		},
+		{
+			name:     "MultiLine",
+			err:      ast.NewSpelParseException("1 +\n\t#x y", 8, ast.MORE_INPUT, "y"),
+			expected: "after parsing a valid expression, there is still more data in the expression: 'y' (position 8, line 2, column 5)\n\t#x y\n\t   ^",
+		},
+		{
+			name: "EvaluationException",
+			err: &ast.SpelEvaluationException{
+				ExpressionString: "naïve[0]",
+				Position:         5,
+				Message:          ast.CANNOT_INDEX_INTO_NULL_VALUE,
+			},
+			expected: "cannot index into a null value (position 5)\nnaïve[0]\n     ^",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := tc.err.FormatWithCaret(); result != tc.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tc.expected, result)
+			}
+		})
+	}
+
+	parser := ast.NewSpelExpressionParser()
+	_, err := parser.ParseExpression("name.?[]")
+	var parseErr *ast.SpelParseException
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected a SpelParseException, got %v", err)
+	}
+	expected := "a required selection expression has not been specified (position 5)\nname.?[]\n     ^"
+	if result := parseErr.FormatWithCaret(); result != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result)
+	}
+}
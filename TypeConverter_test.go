@@ -0,0 +1,253 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/ExpressionWithConversionTests.java
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// Money is a user type converted from strings such as "12.50 EUR"
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func parseMoney(s string) (Money, error) {
+	var units, cents int64
+	var currency string
+	if _, err := fmt.Sscanf(s, "%d.%d %s", &units, &cents, &currency); err != nil {
+		return Money{}, fmt.Errorf("invalid money '%s'", s)
+	}
+	return Money{Cents: units*100 + cents, Currency: currency}, nil
+}
+
+// Wallet holds Money and accepts it as method argument
+type Wallet struct {
+	Balance Money
+}
+
+func (w *Wallet) Deposit(amount Money) int64 {
+	w.Balance.Cents += amount.Cents
+	return w.Balance.Cents
+}
+
+// TestConvertValue tests the built-in conversions of the standard type converter
+func TestConvertValue(t *testing.T) {
+	converter := ast.NewStandardTypeConverter()
+
+	testCases := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"StringToInt", "42", 42},
+		{"TrimmedStringToInt", " 42 ", 42},
+		{"HexStringToInt32", "0x1F", int32(31)},
+		{"NegativeHexStringToInt64", "-#10", int64(-16)},
+		{"StringToFloat", "1.5", 1.5},
+		{"StringToBigInt", "123456789012345678901234567890", mustBigInt("123456789012345678901234567890")},
+		{"IntToString", int32(7), "7"},
+		{"DoubleToString", 1.0, "1.0"},
+		{"BoolToString", true, "true"},
+		{"WideningIntToLong", int32(7), int64(7)},
+		{"NarrowingLongToByte", int64(100), int8(100)},
+		{"DoubleToInt", 3.9, 3},
+		{"IntToBigDecimal", int32(3), big.NewFloat(3)},
+		{"BooleanYes", "yes", true},
+		{"BooleanOn", "ON", true},
+		{"BooleanZero", "0", false},
+		{"BooleanOff", "off", false},
+		{"SliceElements", []interface{}{int32(1), "2", 3.0}, []int{1, 2, 3}},
+		{"StringSlice", []int32{1, 2}, []string{"1", "2"}},
+		{"SliceToArray", []interface{}{"a", "b"}, [2]string{"a", "b"}},
+		{"MapKeysAndValues", map[interface{}]interface{}{"a": "1"}, map[string]int{"a": 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := converter.ConvertValue(tc.value, reflect.TypeOf(tc.expected))
+			if err != nil {
+				t.Fatalf("Failed to convert %v to %T: %v", tc.value, tc.expected, err)
+			}
+			if !sameValue(result, tc.expected) {
+				t.Errorf("Converting %v: expected %v (%T), got %v (%T)", tc.value, tc.expected, tc.expected, result, result)
+			}
+		})
+	}
+}
+
+// TestConvertValueErrors tests conversions that must fail instead of losing data
+func TestConvertValueErrors(t *testing.T) {
+	converter := ast.NewStandardTypeConverter()
+
+	errorCases := []struct {
+		name       string
+		value      interface{}
+		targetType reflect.Type
+		message    string
+	}{
+		{"IntOverflow", int64(300), reflect.TypeOf(int8(0)), "overflow"},
+		{"NegativeToUnsigned", int32(-1), reflect.TypeOf(uint(0)), "overflow"},
+		{"StringOverflow", "2147483648", reflect.TypeOf(int32(0)), ""},
+		{"NotANumber", "abc", reflect.TypeOf(0), ""},
+		{"NotABoolean", "maybe", reflect.TypeOf(false), "invalid boolean value 'maybe'"},
+		{"NullToInt", nil, reflect.TypeOf(0), ""},
+		{"BadSliceElement", []interface{}{"1", "x"}, reflect.TypeOf([]int{}), ""},
+		{"Unsupported", []int{1}, reflect.TypeOf(0), ""},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := converter.ConvertValue(tc.value, tc.targetType)
+			if err == nil {
+				t.Fatalf("Expected error converting %v to %v, but got none", tc.value, tc.targetType)
+			}
+			if !strings.Contains(err.Error(), tc.message) {
+				t.Errorf("Expected error containing '%s', got: %v", tc.message, err)
+			}
+		})
+	}
+}
+
+// TestRegisteredConverter tests that user converters are used for method
+// arguments, assignment and typed results
+func TestRegisteredConverter(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	converter := ast.NewStandardTypeConverter()
+	if err := converter.RegisterConverter(parseMoney); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+	if err := converter.RegisterConverter(func(m Money) string {
+		return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+	}); err != nil {
+		t.Fatalf("Failed to register converter: %v", err)
+	}
+
+	wallet := &Wallet{Balance: Money{Cents: 100, Currency: "EUR"}}
+	context := ast.NewStandardEvaluationContextWithRoot(wallet)
+	context.SetTypeConverter(converter)
+
+	testCases := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"deposit('2.50 EUR')", int64(350)},
+		{"balance = '9.99 EUR'", "9.99 EUR"},
+		{"deposit('0.01 EUR')", int64(1000)},
+	}
+	for _, tc := range testCases {
+		expr, err := parser.ParseExpression(tc.expression)
+		if err != nil {
+			t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+		}
+		result, err := expr.GetValueWithContext(context)
+		if err != nil {
+			t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+		}
+		if result != tc.expected {
+			t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+		}
+	}
+
+	if wallet.Balance != (Money{Cents: 1000, Currency: "EUR"}) {
+		t.Errorf("Expected a balance of 10.00 EUR, got %v", wallet.Balance)
+	}
+
+	expr, err := parser.ParseExpression("balance")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	text, err := ast.GetValueAs[string](expr, context)
+	if err != nil {
+		t.Fatalf("Failed to convert balance: %v", err)
+	}
+	if text != "10.00 EUR" {
+		t.Errorf("Expected '10.00 EUR', got '%s'", text)
+	}
+
+	expr, err = parser.ParseExpression("deposit('ten euros')")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	if _, err := expr.GetValueWithContext(context); err == nil || !strings.Contains(err.Error(), "invalid money") {
+		t.Errorf("Expected the converter error, got: %v", err)
+	}
+
+	for _, invalid := range []interface{}{nil, "not a function", func() Money { return Money{} }, func(string) (Money, int) { return Money{}, 0 }} {
+		if err := converter.RegisterConverter(invalid); err == nil {
+			t.Errorf("Expected error registering %T as converter, but got none", invalid)
+		}
+	}
+}
+
+// TestGetValueAs tests converting expression results to a requested Go type
+func TestGetValueAs(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	parse := func(expression string) *ast.SpelExpression {
+		expr, err := parser.ParseExpression(expression)
+		if err != nil {
+			t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+		}
+		return expr
+	}
+
+	if value, err := ast.GetValueAs[int](parse("1 + 2"), nil); err != nil || value != 3 {
+		t.Errorf("GetValueAs[int]: expected 3, got %v (%v)", value, err)
+	}
+	if value, err := ast.GetValueAs[int64](parse("'4' + '2'"), nil); err != nil || value != 42 {
+		t.Errorf("GetValueAs[int64]: expected 42, got %v (%v)", value, err)
+	}
+	if value, err := ast.GetValueAs[string](parse("10 / 4.0"), nil); err != nil || value != "2.5" {
+		t.Errorf("GetValueAs[string]: expected '2.5', got %v (%v)", value, err)
+	}
+	if value, err := ast.GetValueAs[bool](parse("'yes'"), nil); err != nil || !value {
+		t.Errorf("GetValueAs[bool]: expected true, got %v (%v)", value, err)
+	}
+	if value, err := ast.GetValueAs[[]string](parse("{1, 2, 3}"), nil); err != nil || !reflect.DeepEqual(value, []string{"1", "2", "3"}) {
+		t.Errorf("GetValueAs[[]string]: expected [1 2 3], got %v (%v)", value, err)
+	}
+	if value, err := ast.GetValueAs[interface{}](parse("null"), nil); err != nil || value != nil {
+		t.Errorf("GetValueAs[interface{}]: expected nil, got %v (%v)", value, err)
+	}
+	if value, err := ast.GetValueAs[*User](parse("null"), nil); err != nil || value != nil {
+		t.Errorf("GetValueAs[*User]: expected nil, got %v (%v)", value, err)
+	}
+
+	context := ast.NewStandardEvaluationContextWithRoot(newTestUser())
+	if value, err := ast.GetValueAs[string](parse("age"), context); err != nil || value != "86" {
+		t.Errorf("GetValueAs[string] with context: expected '86', got %v (%v)", value, err)
+	}
+
+	for _, expression := range []string{"'abc'", "null", "300L * 10000000000L", "{1, 'x'}"} {
+		if _, err := ast.GetValueAs[int32](parse(expression), nil); err == nil {
+			t.Errorf("Expected error converting '%s' to int32, but got none", expression)
+		}
+	}
+	if _, err := ast.GetValueAs[int](parse("1 / 0"), nil); err == nil {
+		t.Errorf("Expected evaluation error for '1 / 0', but got none")
+	}
+}
+
+func mustBigInt(s string) *big.Int {
+	value, _ := new(big.Int).SetString(s, 10)
+	return value
+}
+
+// sameValue compares converted values, comparing big numbers by value
+func sameValue(actual, expected interface{}) bool {
+	switch expected := expected.(type) {
+	case *big.Int:
+		actual, ok := actual.(*big.Int)
+		return ok && actual.Cmp(expected) == 0
+	case *big.Float:
+		actual, ok := actual.(*big.Float)
+		return ok && actual.Cmp(expected) == 0
+	}
+	return reflect.DeepEqual(actual, expected)
+}
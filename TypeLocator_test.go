@@ -0,0 +1,252 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/support/StandardTypeLocatorTests.java
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+// TestTypeLocatorImports tests default type names and import prefixes
+func TestTypeLocatorImports(t *testing.T) {
+	locator := ast.NewStandardTypeLocator()
+
+	testCases := []struct {
+		typeName string
+		expected reflect.Type
+	}{
+		{"java.lang.String", reflect.TypeOf("")},
+		{"String", reflect.TypeOf("")},
+		{"Integer", reflect.TypeOf(int32(0))},
+		{"int", reflect.TypeOf(int32(0))},
+		{"java.lang.Long", reflect.TypeOf(int64(0))},
+		{"java.util.List", reflect.TypeOf([]interface{}(nil))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.typeName, func(t *testing.T) {
+			descriptor, err := locator.FindType(tc.typeName)
+			if err != nil {
+				t.Fatalf("Failed to find type '%s': %v", tc.typeName, err)
+			}
+			if descriptor.Type() != tc.expected {
+				t.Errorf("Type '%s': expected %v, got %v", tc.typeName, tc.expected, descriptor.Type())
+			}
+		})
+	}
+
+	if _, err := locator.FindType("Boolean2"); err == nil {
+		t.Error("Expected error for unknown type 'Boolean2'")
+	}
+
+	locator.RegisterType("com.example.Address", reflect.TypeOf(Address{}))
+	if _, err := locator.FindType("Address"); err == nil {
+		t.Error("Expected error for 'Address' before importing com.example")
+	}
+	locator.RegisterImport("com.example")
+	if descriptor, err := locator.FindType("Address"); err != nil || descriptor.Type() != reflect.TypeOf(Address{}) {
+		t.Errorf("Expected Address after importing com.example, got %v, %v", descriptor, err)
+	}
+	locator.RemoveImport("com.example")
+	if _, err := locator.FindType("Address"); err == nil {
+		t.Error("Expected error for 'Address' after removing the import")
+	}
+}
+
+// TestInstanceofOperator tests instanceof against default and registered types
+func TestInstanceofOperator(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	locator := ast.NewStandardTypeLocator()
+	locator.RegisterType("com.example.User", reflect.TypeOf(User{}))
+	locator.RegisterType("java.lang.Runtime", reflect.TypeOf(Address{}))
+	context := ast.NewStandardEvaluationContextWithRoot(newTestUser())
+	context.SetTypeLocator(locator)
+	context.SetVariable("count", int32(5))
+	context.SetVariable("names", []string{"a", "b"})
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   bool
+	}{
+		{"StringIsString", "'xyz' instanceof T(String)", true},
+		{"StringIsObject", "'xyz' instanceof T(Object)", true},
+		{"StringIsNotInteger", "'xyz' instanceof T(Integer)", false},
+		{"IntIsInteger", "42 instanceof T(Integer)", true},
+		{"Int32IsInteger", "#count instanceof T(Integer)", true},
+		{"IntIsNotDouble", "42 instanceof T(Double)", false},
+		{"RealIsDouble", "4.2 instanceof T(double)", true},
+		{"BooleanIsBoolean", "true instanceof T(Boolean)", true},
+		{"NullIsNotString", "null instanceof T(String)", false},
+		{"InlineListIsList", "{1, 2, 3} instanceof T(java.util.List)", true},
+		{"TypedSliceIsList", "#names instanceof T(java.util.List)", true},
+		{"InlineMapIsMap", "{a:1, b:2} instanceof T(java.util.Map)", true},
+		{"ListIsNotMap", "{1, 2} instanceof T(java.util.Map)", false},
+		{"StringSliceType", "#names instanceof T(String[])", true},
+		{"RegisteredPointer", "#root instanceof T(com.example.User)", true},
+		{"RegisteredProperty", "address instanceof T(com.example.User)", false},
+		{"RuntimeRule", "address instanceof T(java.lang.Runtime)", true},
+		{"NotInstanceof", "!('xyz' instanceof T(Integer))", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestInstanceofOperatorErrors tests instanceof with unknown types and non type operands
+func TestInstanceofOperatorErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	errorCases := []struct {
+		name       string
+		expression string
+	}{
+		{"UnknownType", "'xyz' instanceof T(java.lang.Runtime)"},
+		{"StringOperand", "'xyz' instanceof 'java.lang.String'"},
+		{"NullOperand", "'xyz' instanceof null"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValue(); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
+
+// TestStaticMembers tests static function calls and constants on T(...) references
+func TestStaticMembers(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	locator := ast.NewStandardTypeLocator()
+	if err := locator.RegisterFunctions("com.example.Strings", map[string]interface{}{
+		"upper":  strings.ToUpper,
+		"repeat": strings.Repeat,
+	}); err != nil {
+		t.Fatalf("Failed to register functions: %v", err)
+	}
+	locator.RegisterConstants("com.example.Strings", map[string]interface{}{"EMPTY": ""})
+	user := locator.RegisterType("com.example.User", reflect.TypeOf(User{}))
+	user.RegisterFunction("named", func(name string) *User { return &User{Name: name} })
+	context := ast.NewStandardEvaluationContext()
+	context.SetTypeLocator(locator)
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"MathMaxInt", "T(Math).max(1, 2)", int32(2)},
+		{"MathMaxReal", "T(java.lang.Math).max(1.5, 0.5)", 1.5},
+		{"MathMinMixed", "T(Math).min(3, 2.5)", 2.5},
+		{"MathAbs", "T(Math).abs(-4)", int32(4)},
+		{"MathPow", "T(Math).pow(2, 10)", 1024.0},
+		{"MathRound", "T(Math).round(2.5)", int64(3)},
+		{"MathPI", "T(Math).PI", math.Pi},
+		{"IntegerMaxValue", "T(Integer).MAX_VALUE", int32(math.MaxInt32)},
+		{"IntegerMinValue", "T(java.lang.Integer).MIN_VALUE", int32(math.MinInt32)},
+		{"LongMaxValue", "T(Long).MAX_VALUE", int64(math.MaxInt64)},
+		{"IntegerParseInt", "T(Integer).parseInt('42')", int32(42)},
+		{"StringValueOf", "T(String).valueOf(true)", "true"},
+		{"BooleanTrue", "T(Boolean).TRUE", true},
+		{"RegisteredFunction", "T(com.example.Strings).upper('abc')", "ABC"},
+		{"RegisteredFunctionConversion", "T(com.example.Strings).repeat('ab', 2)", "abab"},
+		{"RegisteredConstant", "T(com.example.Strings).EMPTY", ""},
+		{"RegisteredTypeFunction", "T(com.example.User).named('Ada').name", "Ada"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v (%T), got %v (%T)", tc.expression, tc.expected, tc.expected, result, result)
+			}
+		})
+	}
+}
+
+// TestStaticMemberErrors tests unknown static members and forbidden assignments
+func TestStaticMemberErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+
+	errorCases := []struct {
+		name       string
+		expression string
+	}{
+		{"UnknownFunction", "T(Math).wibble(1)"},
+		{"WrongArity", "T(Math).max(1)"},
+		{"UnknownConstant", "T(Integer).WIBBLE"},
+		{"AssignConstant", "T(Integer).MAX_VALUE = 1"},
+		{"ParseError", "T(Integer).parseInt('abc')"},
+		{"UnknownType", "T(java.lang.Runtime).getRuntime()"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+			if _, err := expr.GetValue(); err == nil {
+				t.Errorf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+		})
+	}
+}
+
+// TestEmptyTypeLocator tests that an empty locator only finds registered types
+func TestEmptyTypeLocator(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	locator := ast.NewEmptyTypeLocator()
+	locator.RegisterType("java.lang.String", reflect.TypeOf(""))
+	context := ast.NewStandardEvaluationContext()
+	context.SetTypeLocator(locator)
+
+	allowed, err := parser.ParseExpression("'abc' instanceof T(java.lang.String)")
+	if err != nil {
+		t.Fatalf("Failed to parse expression: %v", err)
+	}
+	if result, err := allowed.GetValueWithContext(context); err != nil || result != true {
+		t.Errorf("Expected true for registered type, got %v, %v", result, err)
+	}
+
+	for _, expression := range []string{"T(String)", "T(Math).max(1, 2)", "T(java.lang.Runtime)"} {
+		expr, err := parser.ParseExpression(expression)
+		if err != nil {
+			t.Fatalf("Failed to parse expression '%s': %v", expression, err)
+		}
+		if _, err := expr.GetValueWithContext(context); err == nil {
+			t.Errorf("Expected '%s' to be refused by the empty type locator", expression)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+// 参考 https://github.com/spring-projects/spring-framework/blob/main/spring-expression/src/test/java/org/springframework/expression/spel/VariableAndFunctionTests.java
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/weaweawe01/ParserSpel/ast"
+)
+
+func reverseString(input string) string {
+	runes := []rune(input)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func varargsFunction(strings ...string) string {
+	return fmt.Sprint(strings)
+}
+
+func varargsFunction2(n int, strings ...string) string {
+	return fmt.Sprintf("%d-%v", n, strings)
+}
+
+func failingFunction(message string) (string, error) {
+	return "", fmt.Errorf("%s", message)
+}
+
+// newFunctionContext creates a context with the functions used by the tests
+func newFunctionContext(t *testing.T) *ast.StandardEvaluationContext {
+	context := ast.NewStandardEvaluationContext()
+	functions := map[string]interface{}{
+		"reverseString":    reverseString,
+		"upper":            strings.ToUpper,
+		"repeat":           reflect.ValueOf(strings.Repeat),
+		"varargsFunction":  varargsFunction,
+		"varargsFunction2": varargsFunction2,
+		"failing":          failingFunction,
+	}
+	for name, function := range functions {
+		if err := context.RegisterFunction(name, function); err != nil {
+			t.Fatalf("Failed to register function '%s': %v", name, err)
+		}
+	}
+	return context
+}
+
+// TestFunctionAccess tests calling registered functions with #fn(args)
+func TestFunctionAccess(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newFunctionContext(t)
+	context.SetVariable("name", "spring")
+	context.SetVariable("double", func(n int) int { return n * 2 })
+
+	testCases := []struct {
+		name       string
+		expression string
+		expected   interface{}
+	}{
+		{"ReverseString", "#reverseString('hello')", "olleh"},
+		{"StandardLibraryFunction", "#upper('abc')", "ABC"},
+		{"VariableArgument", "#upper(#name)", "SPRING"},
+		{"NestedCalls", "#reverseString(#upper('abc'))", "CBA"},
+		{"ReflectValueFunction", "#repeat('ab', 3)", "ababab"},
+		{"ConvertedArgument", "#repeat('ab', '2')", "abab"},
+		{"NumberToString", "#reverseString(123)", "321"},
+		{"VarargsNone", "#varargsFunction()", "[]"},
+		{"VarargsOne", "#varargsFunction('a')", "[a]"},
+		{"VarargsMany", "#varargsFunction('a', 'b', 'c')", "[a b c]"},
+		{"VarargsConverted", "#varargsFunction(1, 2)", "[1 2]"},
+		{"VarargsAfterFixed", "#varargsFunction2(9, 'a', 'b')", "9-[a b]"},
+		{"FunctionInVariable", "#double(21)", 42},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			result, err := expr.GetValueWithContext(context)
+			if err != nil {
+				t.Fatalf("Failed to evaluate expression '%s': %v", tc.expression, err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expression '%s': expected %v, got %v", tc.expression, tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestFunctionAccessErrors tests failures when calling functions
+func TestFunctionAccessErrors(t *testing.T) {
+	parser := ast.NewSpelExpressionParser()
+	context := newFunctionContext(t)
+	context.SetVariable("notAFunction", "abc")
+
+	errorCases := []struct {
+		name          string
+		expression    string
+		expectedError string
+	}{
+		{"UnknownFunction", "#wibble()", "could not be found (position 0)"},
+		{"NotAFunction", "#notAFunction()", "could not be found"},
+		{"TooFewArguments", "#reverseString()", "incorrect number of arguments for function 'reverseString': 0 supplied but function takes 1 (position 0)"},
+		{"TooManyArguments", "#reverseString('a', 'b')", "2 supplied but function takes 1"},
+		{"TooFewVarargsArguments", "'x' + #varargsFunction2()", "0 supplied but function takes 1 (position 6)"},
+		{"UnconvertibleArgument", "#repeat('ab', 'x')", "cannot invoke function 'repeat'"},
+		{"FunctionError", "#failing('boom')", "boom"},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parser.ParseExpression(tc.expression)
+			if err != nil {
+				t.Fatalf("Failed to parse expression '%s': %v", tc.expression, err)
+			}
+
+			_, err = expr.GetValueWithContext(context)
+			if err == nil {
+				t.Fatalf("Expected error when evaluating '%s', but got none", tc.expression)
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%v'", tc.expectedError, err)
+			}
+		})
+	}
+}
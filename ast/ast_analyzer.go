@@ -2,6 +2,8 @@ package ast
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
 )
 
 // AST 分析工具 - 专门用于解析和显示 SpEL 表达式的 AST 结构
@@ -24,7 +26,10 @@ func runASTAnalyzer() {
 		"name matches '[A-Z].*'",
 	}
 
-	parser := NewSpelExpressionParser()
+	// 通过 slog 输出 Token 流和节点构造过程
+	config := NewSpelParserConfiguration()
+	config.Tracer = NewSlogParseTracer(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	parser := NewSpelExpressionParserWithConfig(config)
 
 	for i, expr := range testExpressions {
 		fmt.Printf("\n%d. 表达式: %s\n", i+1, expr)
@@ -35,8 +40,7 @@ func runASTAnalyzer() {
 func analyzeExpression(parser *SpelExpressionParser, expression string) {
 	fmt.Printf("Token 流:\n")
 
-	// 使用调试解析方法
-	expr, err := parser.DoParseExpression(expression)
+	expr, err := parser.ParseExpression(expression)
 	if err != nil {
 		fmt.Printf("❌ 解析错误: %v\n", err)
 		return
@@ -29,6 +29,9 @@ type SpelNode interface {
 	// GetEndPosition returns the end position in the expression string
 	GetEndPosition() int
 
+	// GetSourceSpan returns the line and column of the start and end positions
+	GetSourceSpan() SourceSpan
+
 	// GetChildren returns child nodes
 	GetChildren() []SpelNode
 
@@ -40,6 +43,7 @@ type SpelNode interface {
 type SpelNodeImpl struct {
 	StartPos int
 	EndPos   int
+	Span     SourceSpan // set by the parser once the expression is parsed
 	Children []SpelNode
 }
 
@@ -60,6 +64,14 @@ func (n *SpelNodeImpl) GetEndPosition() int {
 	return n.EndPos
 }
 
+func (n *SpelNodeImpl) GetSourceSpan() SourceSpan {
+	return n.Span
+}
+
+func (n *SpelNodeImpl) setSourceSpan(span SourceSpan) {
+	n.Span = span
+}
+
 func (n *SpelNodeImpl) GetChildren() []SpelNode {
 	return n.Children
 }
@@ -73,7 +85,7 @@ func (n *SpelNodeImpl) IsWritable(state *ExpressionState) bool {
 }
 
 func (n *SpelNodeImpl) SetValue(state *ExpressionState, value interface{}) error {
-	return fmt.Errorf("cannot set value on %T", n)
+	return NewSpelEvaluationException(n.StartPos, NOT_ASSIGNABLE)
 }
 
 // TypedValue represents a value with type information
@@ -89,37 +101,184 @@ func NewTypedValue(value interface{}) *TypedValue {
 	}
 }
 
-// ExpressionState holds the evaluation context and configuration
+// ExpressionState holds the evaluation context and configuration, along with
+// the stacks of active context objects and variable scopes that change while an
+// expression is evaluated
 type ExpressionState struct {
-	EvaluationContext interface{} // Placeholder for evaluation context
+	EvaluationContext EvaluationContext
 	Configuration     *SpelParserConfiguration
 	RootObject        *TypedValue
+	contextObjects    []*TypedValue
+	scopeRootObjects  []*TypedValue
+	variableScopes    []map[string]interface{}
 }
 
 func NewExpressionState(config *SpelParserConfiguration) *ExpressionState {
-	return &ExpressionState{
-		Configuration: config,
-		RootObject:    NewTypedValue(nil),
-	}
+	return NewExpressionStateWithContext(NewStandardEvaluationContext(), nil, config)
 }
 
 func NewExpressionStateWithRoot(config *SpelParserConfiguration, rootObject *TypedValue) *ExpressionState {
+	return NewExpressionStateWithContext(NewStandardEvaluationContext(), rootObject, config)
+}
+
+// NewExpressionStateWithContext creates a state for the given evaluation context.
+// If rootObject is nil the context's root object is used.
+func NewExpressionStateWithContext(context EvaluationContext, rootObject *TypedValue, config *SpelParserConfiguration) *ExpressionState {
+	if context == nil {
+		context = NewStandardEvaluationContext()
+	}
+	if rootObject == nil {
+		rootObject = context.GetRootObject()
+	}
+	if rootObject == nil {
+		rootObject = NewTypedValue(nil)
+	}
 	return &ExpressionState{
-		Configuration: config,
-		RootObject:    rootObject,
+		EvaluationContext: context,
+		Configuration:     config,
+		RootObject:        rootObject,
+	}
+}
+
+// GetRootObject returns the root object of this evaluation
+func (s *ExpressionState) GetRootObject() *TypedValue {
+	return s.RootObject
+}
+
+// GetActiveContextObject returns the object that property and method references
+// are currently evaluated against, defaulting to the root object
+func (s *ExpressionState) GetActiveContextObject() *TypedValue {
+	if len(s.contextObjects) == 0 {
+		return s.RootObject
+	}
+	return s.contextObjects[len(s.contextObjects)-1]
+}
+
+// GetScopeRootContextObject returns the object that method arguments and
+// indexes are evaluated against, regardless of the active context object.
+// This is the root object, or the element being visited inside a selection
+// or projection.
+func (s *ExpressionState) GetScopeRootContextObject() *TypedValue {
+	if len(s.scopeRootObjects) == 0 {
+		return s.RootObject
+	}
+	return s.scopeRootObjects[len(s.scopeRootObjects)-1]
+}
+
+// PushActiveContextObject makes obj the active context object
+func (s *ExpressionState) PushActiveContextObject(obj *TypedValue) {
+	s.contextObjects = append(s.contextObjects, obj)
+}
+
+// PopActiveContextObject restores the previous active context object
+func (s *ExpressionState) PopActiveContextObject() {
+	if len(s.contextObjects) > 0 {
+		s.contextObjects = s.contextObjects[:len(s.contextObjects)-1]
 	}
 }
 
+// EnterScope starts a new variable scope whose scope root is the current active
+// context object
+func (s *ExpressionState) EnterScope() {
+	s.EnterScopeWithVariables(nil)
+}
+
+// EnterScopeWithVariables starts a new variable scope holding the given local variables
+func (s *ExpressionState) EnterScopeWithVariables(variables map[string]interface{}) {
+	scope := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		scope[name] = value
+	}
+	s.variableScopes = append(s.variableScopes, scope)
+	s.scopeRootObjects = append(s.scopeRootObjects, s.GetActiveContextObject())
+}
+
+// ExitScope discards the innermost variable scope
+func (s *ExpressionState) ExitScope() {
+	if len(s.variableScopes) > 0 {
+		s.variableScopes = s.variableScopes[:len(s.variableScopes)-1]
+		s.scopeRootObjects = s.scopeRootObjects[:len(s.scopeRootObjects)-1]
+	}
+}
+
+// SetLocalVariable sets a variable in the innermost scope
+func (s *ExpressionState) SetLocalVariable(name string, value interface{}) {
+	if len(s.variableScopes) == 0 {
+		s.EnterScope()
+	}
+	s.variableScopes[len(s.variableScopes)-1][name] = value
+}
+
+// LookupLocalVariable finds a variable in the scopes, innermost first
+func (s *ExpressionState) LookupLocalVariable(name string) (interface{}, bool) {
+	for i := len(s.variableScopes) - 1; i >= 0; i-- {
+		if value, ok := s.variableScopes[i][name]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// LookupVariable returns the value of a local variable, falling back to the
+// variables of the evaluation context
+func (s *ExpressionState) LookupVariable(name string) interface{} {
+	if value, ok := s.LookupLocalVariable(name); ok {
+		return value
+	}
+	return s.EvaluationContext.LookupVariable(name)
+}
+
+// LookupFunction returns a function registered on the evaluation context, or a
+// variable holding a Go function, as Spring registers functions as variables
+func (s *ExpressionState) LookupFunction(name string) (reflect.Value, bool) {
+	if function, ok := s.EvaluationContext.LookupFunction(name); ok {
+		return function, true
+	}
+	function, ok := s.LookupVariable(name).(reflect.Value)
+	if !ok {
+		function = reflect.ValueOf(s.LookupVariable(name))
+	}
+	if !function.IsValid() || function.Kind() != reflect.Func || function.IsNil() {
+		return reflect.Value{}, false
+	}
+	return function, true
+}
+
+// SetVariable sets a variable in the evaluation context
+func (s *ExpressionState) SetVariable(name string, value interface{}) {
+	s.EvaluationContext.SetVariable(name, value)
+}
+
 // SpelParserConfiguration holds parser configuration
 type SpelParserConfiguration struct {
+	// MaximumExpressionLength, MaxNestingDepth, MaxNodeCount, MaxTokenCount and
+	// MaxStringLiteralLength bound the work spent on an untrusted expression:
+	// the bytes of the expression string, the depth of nested brackets and
+	// unary operators, the nodes of the tree, the tokens and the characters of
+	// a string literal. Zero disables a limit.
 	MaximumExpressionLength int
+	MaxNestingDepth         int
+	MaxNodeCount            int
+	MaxTokenCount           int
+	MaxStringLiteralLength  int
 	AutoGrowCollections     bool
 	AutoGrowNullReferences  bool
+	// LenientBooleans lets and, or, not and ternary conditions accept any
+	// operand, treating null, false, zero and empty strings as false. By
+	// default operands must be booleans, as in Spring.
+	LenientBooleans bool
+	// Tracer, if set, receives the token stream and the nodes of each parsed
+	// expression. See NewSlogParseTracer.
+	Tracer ParseTracer
 }
 
 func NewSpelParserConfiguration() *SpelParserConfiguration {
 	return &SpelParserConfiguration{
 		MaximumExpressionLength: 10000,
+		MaxNestingDepth:         256,
+		MaxNodeCount:            10000,
+		MaxTokenCount:           10000,
+		MaxStringLiteralLength:  10000,
 		AutoGrowCollections:     false,
 		AutoGrowNullReferences:  false,
 	}
@@ -165,14 +324,8 @@ func PrintAST(node SpelNode, level int) {
 	// 打印缩进
 	indent := strings.Repeat("  ", level)
 
-	// 获取节点类型名称
-	nodeType := reflect.TypeOf(node).String()
-	// 移除包名前缀，只保留类型名
-	if idx := strings.LastIndex(nodeType, "."); idx >= 0 {
-		nodeType = nodeType[idx+1:]
-	}
-	// 移除指针符号
-	nodeType = strings.TrimPrefix(nodeType, "*")
+	// 获取节点类型名称（不含包名和指针符号）
+	nodeType := nodeTypeName(node)
 
 	// 打印节点信息
 	fmt.Printf("%s节点类型: %s, 表达式片段: '%s'\n",
@@ -1,9 +1,13 @@
 package ast
 
 import (
+	"cmp"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 // IntLiteral represents an integer literal value in the expression
@@ -67,14 +71,27 @@ func NewRealLiteral(value float64, startPos, endPos int) *RealLiteral {
 	}
 }
 
+// NewFloatLiteral creates a real literal with an F suffix, which evaluates to float32
+func NewFloatLiteral(value float32, startPos, endPos int) *RealLiteral {
+	return &RealLiteral{
+		IntLiteral: NewIntLiteral(value, startPos, endPos),
+	}
+}
+
 func (r *RealLiteral) ToStringAST() string {
-	// Format float64 values with at least one decimal place
-	if val, ok := r.Value.(float64); ok {
+	// Format float values with at least one decimal place
+	switch val := r.Value.(type) {
+	case float64:
 		// If it's a whole number, add .0
 		if val == float64(int64(val)) {
 			return fmt.Sprintf("%.1f", val)
 		}
 		return fmt.Sprintf("%g", val)
+	case float32:
+		if val == float32(int64(val)) {
+			return fmt.Sprintf("%.1f", val)
+		}
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
 	}
 	return fmt.Sprintf("%v", r.Value)
 }
@@ -102,6 +119,38 @@ func (n *NullLiteral) ToStringAST() string {
 	return "null"
 }
 
+// ErrorNode stands in for text that could not be parsed in a tree returned by
+// ParseWithDiagnostics. Evaluating it fails with the message of its diagnostic.
+type ErrorNode struct {
+	*SpelNodeImpl
+	Text       string
+	Diagnostic Diagnostic
+}
+
+func NewErrorNode(text string, diagnostic Diagnostic, startPos, endPos int) *ErrorNode {
+	return &ErrorNode{
+		SpelNodeImpl: NewSpelNodeImpl(startPos, endPos),
+		Text:         text,
+		Diagnostic:   diagnostic,
+	}
+}
+
+func (e *ErrorNode) GetValue(state *ExpressionState) (interface{}, error) {
+	return nil, NewSpelEvaluationException(e.Diagnostic.Position, e.Diagnostic.Message, e.Diagnostic.Inserts...)
+}
+
+func (e *ErrorNode) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
+	value, err := e.GetValue(state)
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedValue(value), nil
+}
+
+func (e *ErrorNode) ToStringAST() string {
+	return "<error>"
+}
+
 // Identifier represents an identifier (variable, property name, etc.)
 type Identifier struct {
 	*SpelNodeImpl
@@ -172,8 +221,51 @@ func NewDirectPropertyOrFieldReference(name string, startPos, endPos int) *Prope
 }
 
 func (p *PropertyOrFieldReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation
-	return p.Name, nil
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		if p.NullSafeNavigation {
+			return nil, nil
+		}
+		return nil, NewSpelEvaluationException(p.StartPos, PROPERTY_OR_FIELD_NOT_READABLE_ON_NULL, p.Name)
+	}
+
+	context := state.EvaluationContext
+	for _, accessor := range context.GetPropertyAccessors() {
+		if accessor.CanRead(context, target, p.Name) {
+			value, err := accessor.Read(context, target, p.Name)
+			return value, atPosition(err, p.StartPos, EXCEPTION_DURING_PROPERTY_READ, p.Name)
+		}
+	}
+	return nil, NewSpelEvaluationException(p.StartPos, PROPERTY_OR_FIELD_NOT_READABLE, p.Name, target)
+}
+
+func (p *PropertyOrFieldReference) IsWritable(state *ExpressionState) bool {
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		return false
+	}
+	context := state.EvaluationContext
+	for _, accessor := range context.GetPropertyAccessors() {
+		if accessor.CanWrite(context, target, p.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PropertyOrFieldReference) SetValue(state *ExpressionState, value interface{}) error {
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		return NewSpelEvaluationException(p.StartPos, PROPERTY_OR_FIELD_NOT_WRITABLE_ON_NULL, p.Name)
+	}
+
+	context := state.EvaluationContext
+	for _, accessor := range context.GetPropertyAccessors() {
+		if accessor.CanWrite(context, target, p.Name) {
+			return atPosition(accessor.Write(context, target, p.Name, value), p.StartPos, EXCEPTION_DURING_PROPERTY_WRITE, p.Name)
+		}
+	}
+	return NewSpelEvaluationException(p.StartPos, PROPERTY_OR_FIELD_NOT_WRITABLE, p.Name, target)
 }
 
 func (p *PropertyOrFieldReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -211,11 +303,16 @@ func (c *CompoundExpression) GetValue(state *ExpressionState) (interface{}, erro
 		return nil, nil
 	}
 
-	var result interface{}
-	var err error
+	result, err := c.Children[0].GetValue(state)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, child := range c.Children {
+	// Each part is evaluated against the result of the previous one
+	for _, child := range c.Children[1:] {
+		state.PushActiveContextObject(NewTypedValue(result))
 		result, err = child.GetValue(state)
+		state.PopActiveContextObject()
 		if err != nil {
 			return nil, err
 		}
@@ -224,6 +321,53 @@ func (c *CompoundExpression) GetValue(state *ExpressionState) (interface{}, erro
 	return result, nil
 }
 
+func (c *CompoundExpression) IsWritable(state *ExpressionState) bool {
+	if len(c.Children) == 0 {
+		return false
+	}
+	writable := false
+	err := c.withLastContext(state, func(last SpelNode) error {
+		writable = last.IsWritable(state)
+		return nil
+	})
+	return err == nil && writable
+}
+
+func (c *CompoundExpression) SetValue(state *ExpressionState, value interface{}) error {
+	if len(c.Children) == 0 {
+		return NewSpelEvaluationException(c.StartPos, NOT_ASSIGNABLE)
+	}
+	return c.withLastContext(state, func(last SpelNode) error {
+		return last.SetValue(state, value)
+	})
+}
+
+// withLastContext evaluates every part but the last and calls fn with the
+// result as the active context object, so that the last part can be written
+func (c *CompoundExpression) withLastContext(state *ExpressionState, fn func(last SpelNode) error) error {
+	last := c.Children[len(c.Children)-1]
+	if len(c.Children) == 1 {
+		return fn(last)
+	}
+
+	target, err := c.Children[0].GetValue(state)
+	if err != nil {
+		return err
+	}
+	for _, child := range c.Children[1 : len(c.Children)-1] {
+		state.PushActiveContextObject(NewTypedValue(target))
+		target, err = child.GetValue(state)
+		state.PopActiveContextObject()
+		if err != nil {
+			return err
+		}
+	}
+
+	state.PushActiveContextObject(NewTypedValue(target))
+	defer state.PopActiveContextObject()
+	return fn(last)
+}
+
 func (c *CompoundExpression) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
 	value, err := c.GetValue(state)
 	if err != nil {
@@ -276,8 +420,27 @@ func NewVariableReference(name string, startPos, endPos int) *VariableReference
 }
 
 func (v *VariableReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation
-	return "#" + v.Name, nil
+	switch v.Name {
+	case "this":
+		return state.GetActiveContextObject().Value, nil
+	case "root":
+		return state.GetRootObject().Value, nil
+	}
+	// Unknown variables evaluate to nil, as in Spring
+	return state.LookupVariable(v.Name), nil
+}
+
+// IsWritable returns true for all variables except #this and #root
+func (v *VariableReference) IsWritable(state *ExpressionState) bool {
+	return v.Name != "this" && v.Name != "root"
+}
+
+func (v *VariableReference) SetValue(state *ExpressionState, value interface{}) error {
+	if !v.IsWritable(state) {
+		return NewSpelEvaluationException(v.StartPos, NOT_ASSIGNABLE)
+	}
+	state.SetVariable(v.Name, value)
+	return nil
 }
 
 func (v *VariableReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -292,10 +455,15 @@ func (v *VariableReference) ToStringAST() string {
 	return "#" + v.Name
 }
 
-// BeanReference represents a bean reference (@bean)
+// FactoryBeanPrefix is prepended to the bean name passed to the BeanResolver
+// for a factory bean reference (&bean)
+const FactoryBeanPrefix = "&"
+
+// BeanReference represents a bean reference (@bean) or factory bean reference (&bean)
 type BeanReference struct {
 	*SpelNodeImpl
-	Name string
+	Name        string
+	FactoryBean bool
 }
 
 func NewBeanReference(name string, startPos, endPos int) *BeanReference {
@@ -305,9 +473,30 @@ func NewBeanReference(name string, startPos, endPos int) *BeanReference {
 	}
 }
 
+// NewFactoryBeanReference creates a reference to the factory of a bean (&bean)
+func NewFactoryBeanReference(name string, startPos, endPos int) *BeanReference {
+	reference := NewBeanReference(name, startPos, endPos)
+	reference.FactoryBean = true
+	return reference
+}
+
+// GetValue resolves the bean through the context's bean resolver. As in Spring,
+// a factory bean reference asks the resolver for "&name".
 func (b *BeanReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation
-	return "@" + b.Name, nil
+	beanName := b.Name
+	if b.FactoryBean {
+		beanName = FactoryBeanPrefix + b.Name
+	}
+
+	resolver := state.EvaluationContext.GetBeanResolver()
+	if resolver == nil {
+		return nil, NewSpelEvaluationException(b.StartPos, NO_BEAN_RESOLVER_REGISTERED, beanName)
+	}
+	bean, err := resolver.Resolve(state.EvaluationContext, beanName)
+	if err != nil {
+		return nil, NewSpelEvaluationException(b.StartPos, EXCEPTION_DURING_BEAN_RESOLUTION, beanName).WithCause(err)
+	}
+	return bean, nil
 }
 
 func (b *BeanReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -319,21 +508,27 @@ func (b *BeanReference) GetTypedValue(state *ExpressionState) (*TypedValue, erro
 }
 
 func (b *BeanReference) ToStringAST() string {
+	if b.FactoryBean {
+		return FactoryBeanPrefix + b.Name
+	}
 	return "@" + b.Name
 }
 
-// parseNumber parses a string token into appropriate numeric type
+// parseNumber parses a string token into the Go type of the Java literal:
+// int32 for int, int64 for long, float64 for double and float32 for float
 func parseNumber(tokenData string, tokenKind TokenKind) (interface{}, error) {
 	switch tokenKind {
 	case LITERAL_INT:
-		return strconv.Atoi(tokenData)
+		i, err := strconv.ParseInt(tokenData, 10, 32)
+		return int32(i), err
 	case LITERAL_LONG:
 		// Remove 'L' suffix
 		data := strings.TrimSuffix(tokenData, "L")
 		data = strings.TrimSuffix(data, "l")
 		return strconv.ParseInt(data, 10, 64)
 	case LITERAL_HEXINT:
-		return strconv.ParseInt(tokenData, 16, 32)
+		i, err := strconv.ParseInt(tokenData, 16, 32)
+		return int32(i), err
 	case LITERAL_HEXLONG:
 		// Remove 'L' suffix
 		data := strings.TrimSuffix(tokenData, "L")
@@ -348,7 +543,8 @@ func parseNumber(tokenData string, tokenKind TokenKind) (interface{}, error) {
 		// Remove 'F' suffix
 		data := strings.TrimSuffix(tokenData, "F")
 		data = strings.TrimSuffix(data, "f")
-		return strconv.ParseFloat(data, 32)
+		f, err := strconv.ParseFloat(data, 32)
+		return float32(f), err
 	default:
 		return nil, fmt.Errorf("unsupported numeric token kind: %v", tokenKind)
 	}
@@ -360,6 +556,52 @@ type MethodReference struct {
 	Name      string
 	NullSafe  bool
 	Arguments []SpelNode
+
+	cachedExecutor atomic.Pointer[cachedMethodExecutor]
+}
+
+// cachedMethodExecutor remembers the executor resolved for a receiver type and
+// argument types by the method resolvers of a context, so that repeated
+// evaluations skip method resolution. The value of every T(...) has the same Go
+// type, so for static functions the descriptor itself is remembered as well.
+type cachedMethodExecutor struct {
+	executor      MethodExecutor
+	targetType    reflect.Type
+	descriptor    *TypeDescriptor
+	argumentTypes []reflect.Type
+	resolvers     []MethodResolver
+}
+
+// isSuitable reports whether the cached executor was resolved for the same
+// receiver type, or the same type descriptor, and argument types by the same
+// resolvers, so that a context with more restrictive resolvers does not reuse it
+func (c *cachedMethodExecutor) isSuitable(target interface{}, argumentTypes []reflect.Type, resolvers []MethodResolver) bool {
+	descriptor, _ := target.(*TypeDescriptor)
+	if c.targetType != reflect.TypeOf(target) || c.descriptor != descriptor ||
+		len(c.argumentTypes) != len(argumentTypes) {
+		return false
+	}
+	for i, argumentType := range argumentTypes {
+		if c.argumentTypes[i] != argumentType {
+			return false
+		}
+	}
+	return sameResolvers(c.resolvers, resolvers)
+}
+
+// sameResolvers reports whether both lists hold identical resolvers. Resolvers
+// of a type that cannot be compared are never considered the same.
+func sameResolvers(a, b []MethodResolver) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if reflect.TypeOf(a[i]) != reflect.TypeOf(b[i]) || a[i] == nil ||
+			!reflect.TypeOf(a[i]).Comparable() || a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func NewMethodReference(nullSafe bool, name string, arguments []SpelNode, startPos, endPos int) *MethodReference {
@@ -372,16 +614,86 @@ func NewMethodReference(nullSafe bool, name string, arguments []SpelNode, startP
 }
 
 func (m *MethodReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation
-	var argValues []interface{}
+	target := state.GetActiveContextObject().Value
+	arguments, err := m.getArguments(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if isNull(target) {
+		if m.NullSafe {
+			return nil, nil
+		}
+		return nil, NewSpelEvaluationException(m.StartPos, METHOD_CALL_ON_NULL_OBJECT_NOT_ALLOWED, m.formatSignature(arguments))
+	}
+
+	context := state.EvaluationContext
+	executor, err := m.findExecutor(context, target, arguments)
+	if err != nil {
+		return nil, err
+	}
+	result, err := executor.Execute(context, target, arguments)
+	if err != nil {
+		return nil, atPosition(err, m.StartPos, EXCEPTION_DURING_METHOD_INVOCATION, m.Name, target)
+	}
+	return result, nil
+}
+
+// findExecutor returns the cached executor if it suits the receiver and argument
+// types, otherwise resolves a new one and caches it. The cache is invalidated
+// whenever a variable or the context object changes type between evaluations,
+// or the method is invoked on T(...) of a different type.
+func (m *MethodReference) findExecutor(context EvaluationContext, target interface{}, arguments []interface{}) (MethodExecutor, error) {
+	types := argumentTypes(arguments)
+	resolvers := context.GetMethodResolvers()
+	if cached := m.cachedExecutor.Load(); cached != nil && cached.isSuitable(target, types, resolvers) {
+		return cached.executor, nil
+	}
+
+	for _, resolver := range resolvers {
+		executor, err := resolver.Resolve(context, target, m.Name, types)
+		if err != nil {
+			return nil, atPosition(err, m.StartPos, PROBLEM_LOCATING_METHOD, m.formatSignature(arguments), target)
+		}
+		if executor != nil {
+			descriptor, _ := target.(*TypeDescriptor)
+			m.cachedExecutor.Store(&cachedMethodExecutor{
+				executor:      executor,
+				targetType:    reflect.TypeOf(target),
+				descriptor:    descriptor,
+				argumentTypes: types,
+				resolvers:     append([]MethodResolver(nil), resolvers...),
+			})
+			return executor, nil
+		}
+	}
+	return nil, NewSpelEvaluationException(m.StartPos, METHOD_NOT_FOUND, m.formatSignature(arguments), target)
+}
+
+// getArguments evaluates the arguments against the scope root rather than the
+// object the method is invoked on, so "#var.echo(name)" reads name from the root
+func (m *MethodReference) getArguments(state *ExpressionState) ([]interface{}, error) {
+	state.PushActiveContextObject(state.GetScopeRootContextObject())
+	defer state.PopActiveContextObject()
+
+	arguments := make([]interface{}, 0, len(m.Arguments))
 	for _, arg := range m.Arguments {
-		val, err := arg.GetValue(state)
+		value, err := arg.GetValue(state)
 		if err != nil {
 			return nil, err
 		}
-		argValues = append(argValues, val)
+		arguments = append(arguments, value)
+	}
+	return arguments, nil
+}
+
+// formatSignature describes the method and its argument types for error messages
+func (m *MethodReference) formatSignature(arguments []interface{}) string {
+	typeNames := make([]string, len(arguments))
+	for i, argument := range arguments {
+		typeNames[i] = fmt.Sprintf("%T", argument)
 	}
-	return fmt.Sprintf("methodCall(%s, %v)", m.Name, argValues), nil
+	return fmt.Sprintf("%s(%s)", m.Name, strings.Join(typeNames, ","))
 }
 
 func (m *MethodReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -407,10 +719,11 @@ func (m *MethodReference) ToStringAST() string {
 // ConstructorReference represents a constructor call in the SpEL AST
 type ConstructorReference struct {
 	*SpelNodeImpl
-	TypeName      string
-	QualifierNode SpelNode // The qualified identifier for the type
-	Arguments     []SpelNode
-	DisplayFormat string // Optional custom display format for array constructors
+	TypeName        string
+	QualifierNode   SpelNode // The qualified identifier for the type
+	Arguments       []SpelNode
+	DisplayFormat   string     // Optional custom display format for array constructors
+	ArrayDimensions []SpelNode // Size expressions of an array constructor, nil for []
 }
 
 func NewConstructorReference(typeName string, qualifierNode SpelNode, arguments []SpelNode, startPos, endPos int) *ConstructorReference {
@@ -447,17 +760,182 @@ func NewConstructorReferenceWithDisplay(typeName string, qualifierNode SpelNode,
 	}
 }
 
+// maxArrayElements limits the number of elements an array constructor may
+// create, as in Spring, so that new int[1024 * 1024][1024 * 1024] fails
+// instead of exhausting memory
+const maxArrayElements = 256 * 1024
+
+// GetValue invokes the constructor found by the context's constructor resolvers,
+// or creates a typed slice for an array constructor
 func (c *ConstructorReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation
-	var argValues []interface{}
+	if len(c.ArrayDimensions) > 0 {
+		return c.createArray(state)
+	}
+
+	arguments := make([]interface{}, 0, len(c.Arguments))
 	for _, arg := range c.Arguments {
-		val, err := arg.GetValue(state)
+		value, err := arg.GetValue(state)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, value)
+	}
+
+	context := state.EvaluationContext
+	types := argumentTypes(arguments)
+	for _, resolver := range context.GetConstructorResolvers() {
+		executor, err := resolver.Resolve(context, c.TypeName, types)
+		if err != nil {
+			return nil, atPosition(err, c.StartPos, CONSTRUCTOR_NOT_FOUND, c.TypeName, formatArgumentTypes(arguments))
+		}
+		if executor != nil {
+			value, err := executor.Execute(context, arguments)
+			if err != nil {
+				return nil, atPosition(err, c.StartPos, CONSTRUCTOR_INVOCATION_PROBLEM, c.TypeName)
+			}
+			return value, nil
+		}
+	}
+	return nil, NewSpelEvaluationException(c.StartPos, CONSTRUCTOR_NOT_FOUND, c.TypeName, formatArgumentTypes(arguments))
+}
+
+// formatArgumentTypes lists the Go types of arguments for a message
+func formatArgumentTypes(arguments []interface{}) string {
+	typeNames := make([]string, len(arguments))
+	for i, argument := range arguments {
+		typeNames[i] = fmt.Sprintf("%T", argument)
+	}
+	return strings.Join(typeNames, ",")
+}
+
+// createArray creates the slice of an array constructor. The array is either
+// sized by the dimension expressions, new int[2][3], or filled from the
+// initializer, new int[]{1, 2} or new int[][]{{1}, {2, 3}}. A size may only be
+// combined with an initializer for a single dimension and must match its length.
+func (c *ConstructorReference) createArray(state *ExpressionState) (interface{}, error) {
+	elementTypeName := c.TypeName
+	for strings.HasSuffix(elementTypeName, "[]") {
+		elementTypeName = strings.TrimSuffix(elementTypeName, "[]")
+	}
+	elementType, err := c.resolveArrayElementType(state.EvaluationContext, elementTypeName)
+	if err != nil {
+		return nil, err
+	}
+	arrayType := elementType
+	for range c.ArrayDimensions {
+		arrayType = reflect.SliceOf(arrayType)
+	}
+
+	converter := state.EvaluationContext.GetTypeConverter()
+	sizes, err := c.getArraySizes(state, converter)
+	if err != nil {
+		return nil, err
+	}
+
+	var initializer *InlineList
+	if len(c.Arguments) > 0 {
+		initializer, _ = c.Arguments[len(c.Arguments)-1].(*InlineList)
+	}
+	if initializer == nil {
+		if len(sizes) != len(c.ArrayDimensions) {
+			return nil, NewSpelEvaluationException(c.StartPos, MISSING_ARRAY_DIMENSION)
+		}
+		return makeArray(arrayType, sizes).Interface(), nil
+	}
+
+	if len(sizes) > 0 && (len(c.ArrayDimensions) > 1 || sizes[0] != len(initializer.Elements)) {
+		return nil, NewSpelEvaluationException(c.StartPos, INITIALIZER_LENGTH_INCORRECT)
+	}
+	values, err := initializer.GetValue(state)
+	if err != nil {
+		return nil, err
+	}
+	array, err := fillArray(arrayType, values.([]interface{}), func(value interface{}, typ reflect.Type) (reflect.Value, error) {
+		// A single character string is a char literal in Java
+		if str, ok := value.(string); ok && elementTypeName == "char" && typ == elementType && len([]rune(str)) == 1 {
+			return reflect.ValueOf([]rune(str)[0]).Convert(typ), nil
+		}
+		return convertArgument(converter, value, typ)
+	})
+	if err != nil {
+		return nil, NewSpelEvaluationException(initializer.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", values), arrayType).WithCause(err)
+	}
+	return array.Interface(), nil
+}
+
+func (c *ConstructorReference) resolveArrayElementType(context EvaluationContext, typeName string) (reflect.Type, error) {
+	for _, resolver := range context.GetConstructorResolvers() {
+		elementType, err := resolver.ResolveArrayElementType(context, typeName)
+		if err != nil {
+			return nil, atPosition(err, c.StartPos, TYPE_NOT_FOUND, typeName)
+		}
+		if elementType != nil {
+			return elementType, nil
+		}
+	}
+	return nil, NewSpelEvaluationException(c.StartPos, TYPE_NOT_FOUND, typeName)
+}
+
+// getArraySizes evaluates the leading sized dimensions, checking the total
+// number of elements stays within maxArrayElements
+func (c *ConstructorReference) getArraySizes(state *ExpressionState, converter TypeConverter) ([]int, error) {
+	var sizes []int
+	total := 1
+	for _, dimension := range c.ArrayDimensions {
+		if dimension == nil {
+			break
+		}
+		value, err := dimension.GetValue(state)
 		if err != nil {
 			return nil, err
 		}
-		argValues = append(argValues, val)
+		size, err := convertArgument(converter, value, intType)
+		if err != nil {
+			return nil, NewSpelEvaluationException(dimension.GetStartPosition(), TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", value), intType).WithCause(err)
+		}
+		if size.Int() < 0 {
+			return nil, NewSpelEvaluationException(dimension.GetStartPosition(), NEGATIVE_ARRAY_DIMENSION, size.Int())
+		}
+		if size.Int() > 0 {
+			if total > maxArrayElements/int(size.Int()) {
+				return nil, NewSpelEvaluationException(c.StartPos, MAX_ARRAY_ELEMENTS_THRESHOLD_EXCEEDED, maxArrayElements)
+			}
+			total *= int(size.Int())
+		}
+		sizes = append(sizes, int(size.Int()))
 	}
-	return fmt.Sprintf("new %s(%v)", c.TypeName, argValues), nil
+	return sizes, nil
+}
+
+// makeArray creates a zeroed slice of arrayType with the given sizes
+func makeArray(arrayType reflect.Type, sizes []int) reflect.Value {
+	array := reflect.MakeSlice(arrayType, sizes[0], sizes[0])
+	if len(sizes) > 1 {
+		for i := 0; i < sizes[0]; i++ {
+			array.Index(i).Set(makeArray(arrayType.Elem(), sizes[1:]))
+		}
+	}
+	return array
+}
+
+// fillArray creates a slice of arrayType from initializer values, filling
+// nested slices from nested lists and converting the elements with convert
+func fillArray(arrayType reflect.Type, values []interface{}, convert func(interface{}, reflect.Type) (reflect.Value, error)) (reflect.Value, error) {
+	array := reflect.MakeSlice(arrayType, len(values), len(values))
+	for i, value := range values {
+		var element reflect.Value
+		var err error
+		if nested, ok := value.([]interface{}); ok && arrayType.Elem().Kind() == reflect.Slice {
+			element, err = fillArray(arrayType.Elem(), nested, convert)
+		} else {
+			element, err = convert(value, arrayType.Elem())
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		array.Index(i).Set(element)
+	}
+	return array, nil
 }
 
 func (c *ConstructorReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -527,17 +1005,12 @@ func NewArrayConstructor(typeName string, elements []SpelNode, startPos, endPos
 	}
 }
 
+// GetValue creates a single dimension slice of the element type from the elements
 func (a *ArrayConstructor) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation
-	var elementValues []interface{}
-	for _, element := range a.Elements {
-		val, err := element.GetValue(state)
-		if err != nil {
-			return nil, err
-		}
-		elementValues = append(elementValues, val)
-	}
-	return fmt.Sprintf("new %s[]{%v}", a.TypeName, elementValues), nil
+	initializer := NewInlineList(a.Elements, a.StartPos, a.EndPos)
+	constructor := NewConstructorReference(a.TypeName, nil, []SpelNode{initializer}, a.StartPos, a.EndPos)
+	constructor.ArrayDimensions = []SpelNode{nil}
+	return constructor.GetValue(state)
 }
 
 func (a *ArrayConstructor) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -627,9 +1100,36 @@ func NewTypeReference(typeName string, startPos, endPos int) *TypeReference {
 	}
 }
 
+// GetValue resolves the type name through the context's type locator and returns
+// its *TypeDescriptor. Array dimensions (String[]) describe slices of the element
+// type and have no static members.
 func (t *TypeReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation - type references typically return Class objects
-	return fmt.Sprintf("Class<%s>", t.TypeName), nil
+	typeName := t.TypeName
+	dimensions := 0
+	for strings.HasSuffix(typeName, "[]") {
+		typeName = strings.TrimSuffix(typeName, "[]")
+		dimensions++
+	}
+
+	locator := state.EvaluationContext.GetTypeLocator()
+	if locator == nil {
+		return nil, NewSpelEvaluationException(t.StartPos, TYPE_NOT_FOUND, t.TypeName)
+	}
+	descriptor, err := locator.FindType(typeName)
+	if err != nil {
+		return nil, atPosition(err, t.StartPos, TYPE_NOT_FOUND, typeName)
+	}
+	if dimensions == 0 {
+		return descriptor, nil
+	}
+	typ := descriptor.Type()
+	if typ == nil {
+		return nil, NewSpelEvaluationException(t.StartPos, ARRAY_ELEMENT_TYPE_NOT_SUPPORTED, typeName)
+	}
+	for i := 0; i < dimensions; i++ {
+		typ = reflect.SliceOf(typ)
+	}
+	return NewTypeDescriptor(t.TypeName, typ), nil
 }
 
 func (t *TypeReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -703,8 +1203,8 @@ func NewInlineList(elements []SpelNode, startPos, endPos int) *InlineList {
 }
 
 func (i *InlineList) GetValue(state *ExpressionState) (interface{}, error) {
-	// Return a slice of element values
-	var elementValues []interface{}
+	// Return a slice of element values, empty rather than nil for {}
+	elementValues := make([]interface{}, 0, len(i.Elements))
 	for _, element := range i.Elements {
 		val, err := element.GetValue(state)
 		if err != nil {
@@ -731,6 +1231,28 @@ func (i *InlineList) ToStringAST() string {
 	return "{" + strings.Join(elements, ",") + "}"
 }
 
+// IndexTarget describes what kind of value an IndexOutOfBoundsError was raised for
+type IndexTarget string
+
+const (
+	IndexTargetCollection IndexTarget = "collection"
+	IndexTargetString     IndexTarget = "string"
+)
+
+// IndexOutOfBoundsError is the cause of the ARRAY_INDEX_OUT_OF_BOUNDS or
+// STRING_INDEX_OUT_OF_BOUNDS exception returned when an Indexer accesses a
+// slice, array or string outside of its bounds
+type IndexOutOfBoundsError struct {
+	Target   IndexTarget
+	Index    int
+	Size     int
+	Position int
+}
+
+func (e *IndexOutOfBoundsError) Error() string {
+	return fmt.Sprintf("index %d out of range [0:%d]", e.Index, e.Size)
+}
+
 // Indexer represents indexing operations like array[index] or list[index]
 type Indexer struct {
 	*SpelNodeImpl
@@ -763,8 +1285,215 @@ func NewNullSafeIndexer(indexExpression SpelNode, startPos, endPos int) *Indexer
 }
 
 func (i *Indexer) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation - would need context about the indexed object
-	return nil, fmt.Errorf("indexer evaluation not yet implemented")
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		if i.NullSafe {
+			return nil, nil
+		}
+		return nil, NewSpelEvaluationException(i.StartPos, CANNOT_INDEX_INTO_NULL_VALUE)
+	}
+
+	value, _ := unwrapValue(reflect.ValueOf(target))
+	index, err := i.getIndex(state, value)
+	if err != nil {
+		return nil, err
+	}
+	return i.getIndexedValue(state, target, value, index)
+}
+
+// getIndexedValue reads the element of target at an evaluated index. value is
+// target with pointers and interfaces unwrapped.
+func (i *Indexer) getIndexedValue(state *ExpressionState, target interface{}, value reflect.Value, index interface{}) (interface{}, error) {
+	converter := state.EvaluationContext.GetTypeConverter()
+	switch value.Kind() {
+	case reflect.Map:
+		key, err := i.mapKey(converter, index, value.Type())
+		if err != nil {
+			return nil, err
+		}
+		return valueToInterface(value.MapIndex(key)), nil
+
+	case reflect.Slice, reflect.Array:
+		position, err := i.toInt(converter, index)
+		if err != nil {
+			return nil, err
+		}
+		if position < 0 || position >= value.Len() {
+			return nil, i.indexOutOfBounds(IndexTargetCollection, position, value.Len())
+		}
+		return valueToInterface(value.Index(position)), nil
+
+	case reflect.String:
+		position, err := i.toInt(converter, index)
+		if err != nil {
+			return nil, err
+		}
+		runes := []rune(value.String())
+		if position < 0 || position >= len(runes) {
+			return nil, i.indexOutOfBounds(IndexTargetString, position, len(runes))
+		}
+		return string(runes[position]), nil
+
+	case reflect.Struct:
+		name, err := converter.ConvertValue(index, reflect.TypeOf(""))
+		if err != nil {
+			return nil, NewSpelEvaluationException(i.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", index), "string").WithCause(err)
+		}
+		context := state.EvaluationContext
+		for _, accessor := range context.GetPropertyAccessors() {
+			if accessor.CanRead(context, target, name.(string)) {
+				value, err := accessor.Read(context, target, name.(string))
+				return value, atPosition(err, i.StartPos, EXCEPTION_DURING_PROPERTY_READ, name)
+			}
+		}
+		return nil, NewSpelEvaluationException(i.StartPos, PROPERTY_OR_FIELD_NOT_READABLE, name, target)
+
+	default:
+		return nil, NewSpelEvaluationException(i.StartPos, INDEXING_NOT_SUPPORTED_FOR_TYPE, target)
+	}
+}
+
+// getIndex evaluates the index expression against the scope root. For maps a
+// bare name such as map[key] is the key itself rather than a property reference.
+func (i *Indexer) getIndex(state *ExpressionState, target reflect.Value) (interface{}, error) {
+	if i.IndexExpression == nil {
+		return nil, NewSpelEvaluationException(i.StartPos, MISSING_INDEX_EXPRESSION)
+	}
+	if reference, ok := i.IndexExpression.(*PropertyOrFieldReference); ok && target.Kind() == reflect.Map {
+		return reference.Name, nil
+	}
+
+	state.PushActiveContextObject(state.GetScopeRootContextObject())
+	defer state.PopActiveContextObject()
+	return i.IndexExpression.GetValue(state)
+}
+
+// mapKey converts an index value to the key type of mapType. A key whose
+// dynamic value cannot be hashed, such as a list, is an error rather than a
+// panic of MapIndex.
+func (i *Indexer) mapKey(converter TypeConverter, index interface{}, mapType reflect.Type) (reflect.Value, error) {
+	key, err := convertArgument(converter, index, mapType.Key())
+	if err != nil {
+		return reflect.Value{}, NewSpelEvaluationException(i.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", index), mapType.Key()).WithCause(err)
+	}
+	if !key.Comparable() {
+		return reflect.Value{}, NewSpelEvaluationException(i.StartPos, UNHASHABLE_MAP_KEY, index)
+	}
+	return key, nil
+}
+
+// toInt converts an index value to an int position
+func (i *Indexer) toInt(converter TypeConverter, index interface{}) (int, error) {
+	position, err := convertArgument(converter, index, reflect.TypeOf(0))
+	if err != nil {
+		return 0, NewSpelEvaluationException(i.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", index), "int").WithCause(err)
+	}
+	return int(position.Int()), nil
+}
+
+// indexOutOfBounds returns the exception for an index outside of a slice,
+// array or string of the given size, caused by an IndexOutOfBoundsError
+func (i *Indexer) indexOutOfBounds(target IndexTarget, index, size int) error {
+	message := ARRAY_INDEX_OUT_OF_BOUNDS
+	if target == IndexTargetString {
+		message = STRING_INDEX_OUT_OF_BOUNDS
+	}
+	cause := &IndexOutOfBoundsError{Target: target, Index: index, Size: size, Position: i.StartPos}
+	return NewSpelEvaluationException(i.StartPos, message, size, index).WithCause(cause)
+}
+
+func (i *Indexer) IsWritable(state *ExpressionState) bool {
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		return false
+	}
+	value, _ := unwrapValue(reflect.ValueOf(target))
+	return isIndexWritable(value)
+}
+
+// isIndexWritable reports whether the elements of an unwrapped target can be set
+func isIndexWritable(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Map:
+		return !value.IsNil()
+	case reflect.Slice:
+		return true
+	case reflect.Array:
+		return value.CanSet()
+	case reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+func (i *Indexer) SetValue(state *ExpressionState, newValue interface{}) error {
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		return NewSpelEvaluationException(i.StartPos, CANNOT_INDEX_INTO_NULL_VALUE)
+	}
+
+	value, _ := unwrapValue(reflect.ValueOf(target))
+	index, err := i.getIndex(state, value)
+	if err != nil {
+		return err
+	}
+	return i.setIndexedValue(state, target, value, index, newValue)
+}
+
+// setIndexedValue writes the element of target at an evaluated index
+func (i *Indexer) setIndexedValue(state *ExpressionState, target interface{}, value reflect.Value, index interface{}, newValue interface{}) error {
+	converter := state.EvaluationContext.GetTypeConverter()
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			return NewSpelEvaluationException(i.StartPos, NOT_ASSIGNABLE)
+		}
+		key, err := i.mapKey(converter, index, value.Type())
+		if err != nil {
+			return err
+		}
+		converted, err := convertArgument(converter, newValue, value.Type().Elem())
+		if err != nil {
+			return NewSpelEvaluationException(i.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", newValue), value.Type().Elem()).WithCause(err)
+		}
+		value.SetMapIndex(key, converted)
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Array && !value.CanSet() {
+			return NewSpelEvaluationException(i.StartPos, NOT_ASSIGNABLE)
+		}
+		position, err := i.toInt(converter, index)
+		if err != nil {
+			return err
+		}
+		if position < 0 || position >= value.Len() {
+			return i.indexOutOfBounds(IndexTargetCollection, position, value.Len())
+		}
+		converted, err := convertArgument(converter, newValue, value.Type().Elem())
+		if err != nil {
+			return NewSpelEvaluationException(i.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", newValue), value.Type().Elem()).WithCause(err)
+		}
+		value.Index(position).Set(converted)
+		return nil
+
+	case reflect.Struct:
+		name, err := converter.ConvertValue(index, reflect.TypeOf(""))
+		if err != nil {
+			return NewSpelEvaluationException(i.StartPos, TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", index), "string").WithCause(err)
+		}
+		context := state.EvaluationContext
+		for _, accessor := range context.GetPropertyAccessors() {
+			if accessor.CanWrite(context, target, name.(string)) {
+				return atPosition(accessor.Write(context, target, name.(string), newValue), i.StartPos, EXCEPTION_DURING_PROPERTY_WRITE, name)
+			}
+		}
+		return NewSpelEvaluationException(i.StartPos, PROPERTY_OR_FIELD_NOT_WRITABLE, name, target)
+
+	default:
+		return NewSpelEvaluationException(i.StartPos, INDEXING_NOT_SUPPORTED_FOR_TYPE, target)
+	}
 }
 
 func (i *Indexer) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -809,12 +1538,13 @@ func NewAssign(left, right SpelNode, startPos, endPos int) *Assign {
 }
 
 func (a *Assign) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation - would set the value and return it
 	rightValue, err := a.Right.GetValue(state)
 	if err != nil {
 		return nil, err
 	}
-	// In a real implementation, this would assign the value to the left-hand side
+	if err := a.Left.SetValue(state, rightValue); err != nil {
+		return nil, err
+	}
 	return rightValue, nil
 }
 
@@ -847,6 +1577,44 @@ const (
 	SelectionLast                       // $[...] - select last matching
 )
 
+// MapEntry is a single map entry, exposing key and value to selection and
+// projection expressions evaluated over a map
+type MapEntry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// sortedMapKeys returns the keys of a map in a deterministic order, since Go
+// maps are unordered and first/last selection depends on iteration order
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.SliceStable(keys, func(i, j int) bool {
+		return compareMapKeys(keys[i], keys[j]) < 0
+	})
+	return keys
+}
+
+// compareMapKeys orders numbers numerically and anything else by its string form
+func compareMapKeys(a, b reflect.Value) int {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.IsValid() && b.IsValid() {
+		switch {
+		case a.CanInt() && b.CanInt():
+			return cmp.Compare(a.Int(), b.Int())
+		case a.CanUint() && b.CanUint():
+			return cmp.Compare(a.Uint(), b.Uint())
+		case a.CanFloat() && b.CanFloat():
+			return cmp.Compare(a.Float(), b.Float())
+		}
+	}
+	return strings.Compare(fmt.Sprint(valueToInterface(a)), fmt.Sprint(valueToInterface(b)))
+}
+
 type Selection struct {
 	*SpelNodeImpl
 	NullSafe bool          // true for safe navigation (?.)
@@ -868,8 +1636,114 @@ func NewSelection(nullSafe bool, kind SelectionKind, criteria SpelNode, startPos
 }
 
 func (s *Selection) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation - would filter the collection based on criteria
-	return nil, fmt.Errorf("selection evaluation not yet implemented")
+	operand := state.GetActiveContextObject().Value
+	if isNull(operand) {
+		if s.NullSafe {
+			return nil, nil
+		}
+		return nil, NewSpelEvaluationException(s.StartPos, INVALID_TYPE_FOR_SELECTION, "null")
+	}
+
+	value, _ := unwrapValue(reflect.ValueOf(operand))
+	switch value.Kind() {
+	case reflect.Map:
+		return s.selectFromMap(state, value)
+	case reflect.Slice, reflect.Array:
+		return s.selectFromSlice(state, value)
+	default:
+		return nil, NewSpelEvaluationException(s.StartPos, INVALID_TYPE_FOR_SELECTION, fmt.Sprintf("%T", operand))
+	}
+}
+
+// selectFromSlice returns the matching elements as a new slice, or the first or
+// last matching element
+func (s *Selection) selectFromSlice(state *ExpressionState, value reflect.Value) (interface{}, error) {
+	result := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), 0, value.Len())
+	for index := 0; index < value.Len(); index++ {
+		element := value.Index(index)
+		selected, err := s.isSelected(state, valueToInterface(element))
+		if err != nil {
+			return nil, err
+		}
+		if !selected {
+			continue
+		}
+		if s.Kind == SelectionFirst {
+			return valueToInterface(element), nil
+		}
+		result = reflect.Append(result, element)
+	}
+
+	if s.Kind == SelectionLast {
+		if result.Len() == 0 {
+			return nil, nil
+		}
+		return valueToInterface(result.Index(result.Len() - 1)), nil
+	}
+	if s.Kind == SelectionFirst {
+		return nil, nil
+	}
+	return result.Interface(), nil
+}
+
+// selectFromMap returns a map of the matching entries. Each entry is evaluated
+// as a MapEntry, so the criteria can refer to key and value. For first and last
+// selection a map holding the single matching entry is returned.
+func (s *Selection) selectFromMap(state *ExpressionState, value reflect.Value) (interface{}, error) {
+	result := reflect.MakeMap(value.Type())
+	var lastKey reflect.Value
+	for _, key := range sortedMapKeys(value) {
+		entry := &MapEntry{Key: valueToInterface(key), Value: valueToInterface(value.MapIndex(key))}
+		selected, err := s.isSelected(state, entry)
+		if err != nil {
+			return nil, err
+		}
+		if !selected {
+			continue
+		}
+		result.SetMapIndex(key, value.MapIndex(key))
+		if s.Kind == SelectionFirst {
+			return result.Interface(), nil
+		}
+		lastKey = key
+	}
+
+	if s.Kind == SelectionLast {
+		if !lastKey.IsValid() {
+			return nil, nil
+		}
+		last := reflect.MakeMap(value.Type())
+		last.SetMapIndex(lastKey, value.MapIndex(lastKey))
+		return last.Interface(), nil
+	}
+	if s.Kind == SelectionFirst {
+		return nil, nil
+	}
+	return result.Interface(), nil
+}
+
+// isSelected evaluates the criteria with element as the active context object
+func (s *Selection) isSelected(state *ExpressionState, element interface{}) (bool, error) {
+	if s.Criteria == nil {
+		return false, NewSpelEvaluationException(s.StartPos, MISSING_SELECTION_EXPRESSION)
+	}
+
+	state.PushActiveContextObject(NewTypedValue(element))
+	state.EnterScope()
+	defer func() {
+		state.ExitScope()
+		state.PopActiveContextObject()
+	}()
+
+	result, err := s.Criteria.GetValue(state)
+	if err != nil {
+		return false, err
+	}
+	selected, ok := result.(bool)
+	if !ok {
+		return false, NewSpelEvaluationException(s.StartPos, RESULT_OF_SELECTION_CRITERIA_IS_NOT_BOOLEAN, result)
+	}
+	return selected, nil
 }
 
 func (s *Selection) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -919,8 +1793,51 @@ func NewFunctionReference(functionName string, arguments []SpelNode, startPos, e
 }
 
 func (f *FunctionReference) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation - would call the registered function
-	return nil, fmt.Errorf("function evaluation not yet implemented")
+	function, ok := state.LookupFunction(f.FunctionName)
+	if !ok {
+		return nil, NewSpelEvaluationException(f.StartPos, FUNCTION_NOT_DEFINED, f.FunctionName)
+	}
+
+	arguments, err := f.getArguments(state)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := function.Type()
+	if !hasValidArity(signature, len(arguments)) {
+		declared := signature.NumIn()
+		if signature.IsVariadic() {
+			declared--
+		}
+		return nil, NewSpelEvaluationException(f.StartPos, INCORRECT_NUMBER_OF_ARGUMENTS_TO_FUNCTION,
+			f.FunctionName, len(arguments), declared)
+	}
+
+	in, spread, err := convertArguments(state.EvaluationContext.GetTypeConverter(), signature, arguments)
+	if err != nil {
+		return nil, NewSpelEvaluationException(f.StartPos, EXCEPTION_DURING_FUNCTION_CALL, f.FunctionName).WithCause(err)
+	}
+	result, err := invoke(function, in, spread, fmt.Sprintf("function '%s'", f.FunctionName))
+	if err != nil {
+		return nil, atPosition(err, f.StartPos, EXCEPTION_DURING_FUNCTION_CALL, f.FunctionName)
+	}
+	return result, nil
+}
+
+// getArguments evaluates the arguments against the scope root
+func (f *FunctionReference) getArguments(state *ExpressionState) ([]interface{}, error) {
+	state.PushActiveContextObject(state.GetScopeRootContextObject())
+	defer state.PopActiveContextObject()
+
+	arguments := make([]interface{}, 0, len(f.Arguments))
+	for _, arg := range f.Arguments {
+		value, err := arg.GetValue(state)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, value)
+	}
+	return arguments, nil
 }
 
 func (f *FunctionReference) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -968,24 +1885,11 @@ func NewTernary(condition, trueValue, falseValue SpelNode, startPos, endPos int)
 
 func (t *Ternary) GetValue(state *ExpressionState) (interface{}, error) {
 	// Evaluate condition
-	conditionValue, err := t.Condition.GetValue(state)
+	conditionBool, err := booleanValue(state, t.Condition)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to boolean
-	var conditionBool bool
-	switch v := conditionValue.(type) {
-	case bool:
-		conditionBool = v
-	case string:
-		conditionBool = v != ""
-	case int:
-		conditionBool = v != 0
-	default:
-		conditionBool = v != nil
-	}
-
 	// Return appropriate value
 	if conditionBool {
 		return t.TrueValue.GetValue(state)
@@ -1094,6 +1998,7 @@ func (e *Elvis) ToStringAST() string {
 type Projection struct {
 	*SpelNodeImpl
 	ProjectionExpression SpelNode // The expression to project from each element
+	NullSafe             bool     // true for ?.![...], false for .![...]
 }
 
 func NewProjection(projectionExpression SpelNode, startPos, endPos int) *Projection {
@@ -1104,12 +2009,69 @@ func NewProjection(projectionExpression SpelNode, startPos, endPos int) *Project
 	return &Projection{
 		SpelNodeImpl:         NewSpelNodeImpl(startPos, endPos, children...),
 		ProjectionExpression: projectionExpression,
+		NullSafe:             false,
 	}
 }
 
+func NewNullSafeProjection(projectionExpression SpelNode, startPos, endPos int) *Projection {
+	projection := NewProjection(projectionExpression, startPos, endPos)
+	projection.NullSafe = true
+	return projection
+}
+
 func (p *Projection) GetValue(state *ExpressionState) (interface{}, error) {
-	// Placeholder implementation - would project the expression over a collection
-	return nil, fmt.Errorf("projection evaluation not yet implemented")
+	operand := state.GetActiveContextObject().Value
+	if isNull(operand) {
+		if p.NullSafe {
+			return nil, nil
+		}
+		return nil, NewSpelEvaluationException(p.StartPos, PROJECTION_NOT_SUPPORTED_ON_TYPE, "null")
+	}
+
+	value, _ := unwrapValue(reflect.ValueOf(operand))
+	switch value.Kind() {
+	case reflect.Map:
+		// Each entry is projected as a MapEntry, so the expression can refer to key and value
+		result := make([]interface{}, 0, value.Len())
+		for _, key := range sortedMapKeys(value) {
+			entry := &MapEntry{Key: valueToInterface(key), Value: valueToInterface(value.MapIndex(key))}
+			projected, err := p.project(state, entry)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, projected)
+		}
+		return result, nil
+
+	case reflect.Slice, reflect.Array:
+		result := make([]interface{}, 0, value.Len())
+		for index := 0; index < value.Len(); index++ {
+			projected, err := p.project(state, valueToInterface(value.Index(index)))
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, projected)
+		}
+		return result, nil
+
+	default:
+		return nil, NewSpelEvaluationException(p.StartPos, PROJECTION_NOT_SUPPORTED_ON_TYPE, fmt.Sprintf("%T", operand))
+	}
+}
+
+// project evaluates the projection expression with element as the active context object
+func (p *Projection) project(state *ExpressionState, element interface{}) (interface{}, error) {
+	if p.ProjectionExpression == nil {
+		return nil, NewSpelEvaluationException(p.StartPos, MISSING_PROJECTION_EXPRESSION)
+	}
+
+	state.PushActiveContextObject(NewTypedValue(element))
+	state.EnterScope()
+	defer func() {
+		state.ExitScope()
+		state.PopActiveContextObject()
+	}()
+	return p.ProjectionExpression.GetValue(state)
 }
 
 func (p *Projection) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -1125,7 +2087,12 @@ func (p *Projection) ToStringAST() string {
 	if p.ProjectionExpression != nil {
 		projectionStr = p.ProjectionExpression.ToStringAST()
 	}
-	return ".![" + projectionStr + "]"
+
+	prefix := "."
+	if p.NullSafe {
+		prefix = "?."
+	}
+	return prefix + "![" + projectionStr + "]"
 }
 
 // InlineMap represents a map literal like {key1:value1,key2:value2}
@@ -1154,10 +2121,13 @@ func (i *InlineMap) GetValue(state *ExpressionState) (interface{}, error) {
 	// Return a map of key-value pairs
 	result := make(map[interface{}]interface{})
 	for _, pair := range i.KeyValuePairs {
-		key, err := pair.Key.GetValue(state)
+		key, err := i.getKey(state, pair.Key)
 		if err != nil {
 			return nil, err
 		}
+		if key != nil && !reflect.ValueOf(key).Comparable() {
+			return nil, NewSpelEvaluationException(pair.Key.GetStartPosition(), UNHASHABLE_MAP_KEY, key)
+		}
 		value, err := pair.Value.GetValue(state)
 		if err != nil {
 			return nil, err
@@ -1167,6 +2137,14 @@ func (i *InlineMap) GetValue(state *ExpressionState) (interface{}, error) {
 	return result, nil
 }
 
+// getKey evaluates a map key; unquoted identifiers such as {a:1} are used as string keys
+func (i *InlineMap) getKey(state *ExpressionState, key SpelNode) (interface{}, error) {
+	if ref, ok := key.(*PropertyOrFieldReference); ok && ref.IsDirectReference {
+		return ref.Name, nil
+	}
+	return key.GetValue(state)
+}
+
 func (i *InlineMap) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
 	val, err := i.GetValue(state)
 	if err != nil {
@@ -1,11 +1,13 @@
 package ast
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
-	"math"
+	"math/big"
 	"reflect"
 	"regexp"
-	"strconv"
+	"strings"
 )
 
 // BinaryOperator represents a binary operator
@@ -46,16 +48,20 @@ func (op *OpPlus) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
+	// Handle numeric addition
+	if isNumber(leftVal) && isNumber(rightVal) {
+		return addNumbers(leftVal, rightVal)
+	}
+
 	// Handle string concatenation
 	if leftStr, ok := leftVal.(string); ok {
-		return leftStr + fmt.Sprintf("%v", rightVal), nil
+		return leftStr + toSpelString(rightVal), nil
 	}
 	if rightStr, ok := rightVal.(string); ok {
-		return fmt.Sprintf("%v", leftVal) + rightStr, nil
+		return toSpelString(leftVal) + rightStr, nil
 	}
 
-	// Handle numeric addition
-	return addNumbers(leftVal, rightVal)
+	return operate(state, OperationAdd, leftVal, rightVal, op.StartPos)
 }
 
 func (op *OpPlus) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -102,6 +108,9 @@ func (op *OpMinus) GetValue(state *ExpressionState) (interface{}, error) {
 
 	// If this is a unary minus operation (Right is nil)
 	if op.Right == nil {
+		if !isNumber(leftVal) {
+			return operate(state, OperationSubtract, leftVal, nil, op.StartPos)
+		}
 		return negateNumber(leftVal)
 	}
 
@@ -111,6 +120,9 @@ func (op *OpMinus) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
+	if !isNumber(leftVal) || !isNumber(rightVal) {
+		return operate(state, OperationSubtract, leftVal, rightVal, op.StartPos)
+	}
 	return subtractNumbers(leftVal, rightVal)
 }
 
@@ -154,9 +166,33 @@ func (op *OpMultiply) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
+	// 'abc' * 2 repeats the text, as in Java
+	if text, ok := leftVal.(string); ok {
+		if rank := numericRankOf(rightVal); rank == intRank || rank == goIntRank {
+			return repeatText(text, int(toInt64(rightVal)), op.StartPos)
+		}
+	}
+
+	if !isNumber(leftVal) || !isNumber(rightVal) {
+		return operate(state, OperationMultiply, leftVal, rightVal, op.StartPos)
+	}
 	return multiplyNumbers(leftVal, rightVal)
 }
 
+// maxRepeatedTextSize is the maximum length of text produced by repeating a
+// string with the * operator
+const maxRepeatedTextSize = 256
+
+func repeatText(text string, count int, position int) (string, error) {
+	if count < 0 {
+		return "", NewSpelEvaluationException(position, NEGATIVE_REPEATED_TEXT_COUNT, count)
+	}
+	if count > 0 && len(text) > maxRepeatedTextSize/count {
+		return "", NewSpelEvaluationException(position, MAX_REPEATED_TEXT_SIZE_EXCEEDED, maxRepeatedTextSize)
+	}
+	return strings.Repeat(text, count), nil
+}
+
 func (op *OpMultiply) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
 	value, err := op.GetValue(state)
 	if err != nil {
@@ -172,11 +208,13 @@ func (op *OpMultiply) ToStringAST() string {
 // OpDivide represents division operator
 type OpDivide struct {
 	*BinaryOperator
+	OperatorPos int // position of the '/' token, where division by zero is reported
 }
 
 func NewOpDivide(left, right SpelNode, startPos, endPos int) *OpDivide {
 	return &OpDivide{
 		BinaryOperator: NewBinaryOperator(left, right, startPos, endPos),
+		OperatorPos:    startPos,
 	}
 }
 
@@ -191,7 +229,14 @@ func (op *OpDivide) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
-	return divideNumbers(leftVal, rightVal)
+	if !isNumber(leftVal) || !isNumber(rightVal) {
+		return operate(state, OperationDivide, leftVal, rightVal, op.StartPos)
+	}
+	result, err := divideNumbers(leftVal, rightVal)
+	if errors.Is(err, ErrDivisionByZero) {
+		return nil, NewSpelEvaluationException(op.OperatorPos, DIVISION_BY_ZERO).WithCause(err)
+	}
+	return result, err
 }
 
 func (op *OpDivide) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -209,11 +254,13 @@ func (op *OpDivide) ToStringAST() string {
 // OpModulus represents modulo operator
 type OpModulus struct {
 	*BinaryOperator
+	OperatorPos int // position of the '%' token, where division by zero is reported
 }
 
 func NewOpModulus(left, right SpelNode, startPos, endPos int) *OpModulus {
 	return &OpModulus{
 		BinaryOperator: NewBinaryOperator(left, right, startPos, endPos),
+		OperatorPos:    startPos,
 	}
 }
 
@@ -228,7 +275,14 @@ func (op *OpModulus) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
-	return moduloNumbers(leftVal, rightVal)
+	if !isNumber(leftVal) || !isNumber(rightVal) {
+		return operate(state, OperationModulus, leftVal, rightVal, op.StartPos)
+	}
+	result, err := moduloNumbers(leftVal, rightVal)
+	if errors.Is(err, ErrDivisionByZero) {
+		return nil, NewSpelEvaluationException(op.OperatorPos, DIVISION_BY_ZERO).WithCause(err)
+	}
+	return result, err
 }
 
 func (op *OpModulus) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -265,6 +319,9 @@ func (op *OperatorPower) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
+	if !isNumber(leftVal) || !isNumber(rightVal) {
+		return operate(state, OperationPower, leftVal, rightVal, op.StartPos)
+	}
 	return powerNumbers(leftVal, rightVal)
 }
 
@@ -304,7 +361,7 @@ func (op *OpEQ) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
-	return reflect.DeepEqual(leftVal, rightVal), nil
+	return equalityCheck(leftVal, rightVal), nil
 }
 
 func (op *OpEQ) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -341,7 +398,7 @@ func (op *OpNE) GetValue(state *ExpressionState) (interface{}, error) {
 		return nil, err
 	}
 
-	return !reflect.DeepEqual(leftVal, rightVal), nil
+	return !equalityCheck(leftVal, rightVal), nil
 }
 
 func (op *OpNE) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -368,17 +425,7 @@ func NewOpGT(left, right SpelNode, startPos, endPos int) *OpGT {
 }
 
 func (op *OpGT) GetValue(state *ExpressionState) (interface{}, error) {
-	leftVal, err := op.Left.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	rightVal, err := op.Right.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	return compareNumbers(leftVal, rightVal) > 0, nil
+	return op.compareOperands(state, func(comparison int) bool { return comparison > 0 })
 }
 
 func (op *OpGT) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -405,17 +452,7 @@ func NewOpLT(left, right SpelNode, startPos, endPos int) *OpLT {
 }
 
 func (op *OpLT) GetValue(state *ExpressionState) (interface{}, error) {
-	leftVal, err := op.Left.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	rightVal, err := op.Right.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	return compareNumbers(leftVal, rightVal) < 0, nil
+	return op.compareOperands(state, func(comparison int) bool { return comparison < 0 })
 }
 
 func (op *OpLT) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -442,17 +479,7 @@ func NewOpLE(left, right SpelNode, startPos, endPos int) *OpLE {
 }
 
 func (op *OpLE) GetValue(state *ExpressionState) (interface{}, error) {
-	leftVal, err := op.Left.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	rightVal, err := op.Right.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	return compareNumbers(leftVal, rightVal) <= 0, nil
+	return op.compareOperands(state, func(comparison int) bool { return comparison <= 0 })
 }
 
 func (op *OpLE) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -479,17 +506,7 @@ func NewOpGE(left, right SpelNode, startPos, endPos int) *OpGE {
 }
 
 func (op *OpGE) GetValue(state *ExpressionState) (interface{}, error) {
-	leftVal, err := op.Left.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	rightVal, err := op.Right.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	return compareNumbers(leftVal, rightVal) >= 0, nil
+	return op.compareOperands(state, func(comparison int) bool { return comparison >= 0 })
 }
 
 func (op *OpGE) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -520,22 +537,21 @@ func NewOpAnd(left, right SpelNode, startPos, endPos int) *OpAnd {
 }
 
 func (op *OpAnd) GetValue(state *ExpressionState) (interface{}, error) {
-	leftVal, err := op.Left.GetValue(state)
+	leftVal, err := booleanValue(state, op.Left)
 	if err != nil {
 		return nil, err
 	}
 
 	// Short-circuit evaluation
-	if !isTruthy(leftVal) {
+	if !leftVal {
 		return false, nil
 	}
 
-	rightVal, err := op.Right.GetValue(state)
+	rightVal, err := booleanValue(state, op.Right)
 	if err != nil {
 		return nil, err
 	}
-
-	return isTruthy(rightVal), nil
+	return rightVal, nil
 }
 
 func (op *OpAnd) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -562,22 +578,21 @@ func NewOpOr(left, right SpelNode, startPos, endPos int) *OpOr {
 }
 
 func (op *OpOr) GetValue(state *ExpressionState) (interface{}, error) {
-	leftVal, err := op.Left.GetValue(state)
+	leftVal, err := booleanValue(state, op.Left)
 	if err != nil {
 		return nil, err
 	}
 
 	// Short-circuit evaluation
-	if isTruthy(leftVal) {
+	if leftVal {
 		return true, nil
 	}
 
-	rightVal, err := op.Right.GetValue(state)
+	rightVal, err := booleanValue(state, op.Right)
 	if err != nil {
 		return nil, err
 	}
-
-	return isTruthy(rightVal), nil
+	return rightVal, nil
 }
 
 func (op *OpOr) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -620,12 +635,12 @@ func NewOperatorNot(child SpelNode, startPos, endPos int) *OperatorNot {
 }
 
 func (op *OperatorNot) GetValue(state *ExpressionState) (interface{}, error) {
-	childVal, err := op.Child.GetValue(state)
+	childVal, err := booleanValue(state, op.Child)
 	if err != nil {
 		return nil, err
 	}
 
-	return !isTruthy(childVal), nil
+	return !childVal, nil
 }
 
 func (op *OperatorNot) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -667,7 +682,7 @@ func (op *OperatorMatches) GetValue(state *ExpressionState) (interface{}, error)
 
 	matched, err := regexp.MatchString(pattern, str)
 	if err != nil {
-		return nil, fmt.Errorf("invalid regex pattern: %v", err)
+		return nil, NewSpelEvaluationException(op.Right.GetStartPosition(), INVALID_PATTERN, pattern).WithCause(err)
 	}
 
 	return matched, nil
@@ -685,134 +700,210 @@ func (op *OperatorMatches) ToStringAST() string {
 	return fmt.Sprintf("(%s matches %s)", op.Left.ToStringAST(), op.Right.ToStringAST())
 }
 
+// OperatorInstanceof represents the instanceof operator (e.g., "x instanceof T(String)")
+type OperatorInstanceof struct {
+	*BinaryOperator
+}
+
+func NewOperatorInstanceof(left, right SpelNode, startPos, endPos int) *OperatorInstanceof {
+	return &OperatorInstanceof{
+		BinaryOperator: NewBinaryOperator(left, right, startPos, endPos),
+	}
+}
+
+// GetValue checks the left operand against the type produced by the right operand,
+// which must be a type reference. A null left operand is never an instance.
+func (op *OperatorInstanceof) GetValue(state *ExpressionState) (interface{}, error) {
+	leftVal, err := op.Left.GetValue(state)
+	if err != nil {
+		return nil, err
+	}
+
+	rightVal, err := op.Right.GetValue(state)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, ok := rightVal.(*TypeDescriptor)
+	if !ok {
+		return nil, NewSpelEvaluationException(op.Right.GetStartPosition(), INSTANCEOF_OPERATOR_NEEDS_CLASS_OPERAND, rightVal)
+	}
+
+	return isInstanceOf(leftVal, descriptor.Type()), nil
+}
+
+func (op *OperatorInstanceof) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
+	value, err := op.GetValue(state)
+	if err != nil {
+		return nil, err
+	}
+	return NewTypedValue(value), nil
+}
+
+func (op *OperatorInstanceof) ToStringAST() string {
+	return fmt.Sprintf("(%s instanceof %s)", op.Left.ToStringAST(), op.Right.ToStringAST())
+}
+
 // Helper functions for numeric operations
 
+// operate applies an arithmetic operation to operands the operator does not
+// support natively, through the context's operator overloader. As in Spring it
+// fails if there is no overloader or it does not override the operation. The
+// right operand of a unary minus is nil.
+func operate(state *ExpressionState, operation Operation, left, right interface{}, position int) (interface{}, error) {
+	if overloader := state.EvaluationContext.GetOperatorOverloader(); overloader != nil {
+		overrides, err := overloader.OverridesOperation(operation, left, right)
+		if err != nil {
+			return nil, atPosition(err, position, OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES, operation, left, right)
+		}
+		if overrides {
+			result, err := overloader.Operate(operation, left, right)
+			if err != nil {
+				return nil, atPosition(err, position, OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES, operation, left, right)
+			}
+			return result, nil
+		}
+	}
+	return nil, NewSpelEvaluationException(position, OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES, operation, left, right)
+}
+
 func addNumbers(left, right interface{}) (interface{}, error) {
-	return performNumericOperation(left, right, func(a, b float64) float64 { return a + b })
+	return arithmetic('+', left, right)
 }
 
 func subtractNumbers(left, right interface{}) (interface{}, error) {
-	return performNumericOperation(left, right, func(a, b float64) float64 { return a - b })
+	return arithmetic('-', left, right)
 }
 
 func multiplyNumbers(left, right interface{}) (interface{}, error) {
-	return performNumericOperation(left, right, func(a, b float64) float64 { return a * b })
+	return arithmetic('*', left, right)
 }
 
 func divideNumbers(left, right interface{}) (interface{}, error) {
-	return performNumericOperation(left, right, func(a, b float64) float64 {
-		if b == 0 {
-			return 0 // Handle division by zero
-		}
-		return a / b
-	})
+	return arithmetic('/', left, right)
 }
 
 func moduloNumbers(left, right interface{}) (interface{}, error) {
-	return performNumericOperation(left, right, func(a, b float64) float64 {
-		if b == 0 {
-			return 0 // Handle modulo by zero
-		}
-		// Use Go's math package behavior for floating point modulo
-		// For integers, this works the same as % operator
-		return math.Mod(a, b)
-	})
+	return arithmetic('%', left, right)
 }
 
 func powerNumbers(left, right interface{}) (interface{}, error) {
-	return performNumericOperation(left, right, func(a, b float64) float64 {
-		// Use Go's math.Pow function for power operation
-		return math.Pow(a, b)
-	})
+	return power(left, right)
 }
 
-func negateNumber(operand interface{}) (interface{}, error) {
-	switch v := operand.(type) {
-	case int:
-		return -v, nil
-	case int32:
-		return -v, nil
-	case int64:
-		return -v, nil
-	case float32:
-		return -v, nil
-	case float64:
-		return -v, nil
-	default:
-		// Try to convert to number and negate
-		num, err := toNumber(operand)
-		if err != nil {
-			return nil, fmt.Errorf("cannot negate non-numeric value: %v", operand)
-		}
-		return -num, nil
+// equalityCheck compares operands for == and !=. Numbers are equal if their
+// promoted values are, so 1 == 1L and 1 == 1.0 hold; other values must be deeply equal.
+func equalityCheck(left, right interface{}) bool {
+	if isNumber(left) && isNumber(right) {
+		return numbersEqual(left, right)
 	}
+	return reflect.DeepEqual(left, right)
 }
 
-func performNumericOperation(left, right interface{}, op func(float64, float64) float64) (interface{}, error) {
-	// For string operands in multiplication, treat as string repetition or concatenation
-	if _, isLeftString := left.(string); isLeftString {
-		if _, isRightString := right.(string); isRightString {
-			// Both strings - not a numeric operation
-			return nil, fmt.Errorf("cannot perform numeric operation on two strings")
-		}
-	}
-
-	leftNum, err := toNumber(left)
+// compareOperands evaluates both operands and applies test to their comparison.
+// Numbers are compared after binary numeric promotion, and a NaN operand makes
+// every comparison false, as in Java.
+func (op *BinaryOperator) compareOperands(state *ExpressionState, test func(comparison int) bool) (interface{}, error) {
+	leftVal, err := op.Left.GetValue(state)
 	if err != nil {
 		return nil, err
 	}
 
-	rightNum, err := toNumber(right)
+	rightVal, err := op.Right.GetValue(state)
 	if err != nil {
 		return nil, err
 	}
 
-	result := op(leftNum, rightNum)
-
-	// Try to preserve integer type if possible
-	if float64(int64(result)) == result {
-		return int64(result), nil
+	if isNumber(leftVal) && isNumber(rightVal) {
+		comparison, ordered := compareNumbers(leftVal, rightVal)
+		return ordered && test(comparison), nil
 	}
-
-	return result, nil
+	comparison, ok := compareValues(leftVal, rightVal)
+	if !ok {
+		return nil, NewSpelEvaluationException(op.StartPos, NOT_COMPARABLE, leftVal, rightVal)
+	}
+	return test(comparison), nil
+}
+
+// compareValues compares two values like SpEL's StandardTypeComparator: null is
+// smaller than anything else, numbers are promoted, strings compare
+// lexicographically, false is smaller than true and values with a
+// Compare(other) int method, like time.Time, are compared with it. ok is false
+// for values that cannot be compared.
+func compareValues(left, right interface{}) (comparison int, ok bool) {
+	switch {
+	case left == nil && right == nil:
+		return 0, true
+	case left == nil:
+		return -1, true
+	case right == nil:
+		return 1, true
+	case isNumber(left) && isNumber(right):
+		return compareNumbers(left, right)
+	}
+
+	l, r := reflect.ValueOf(left), reflect.ValueOf(right)
+	switch {
+	case l.Kind() == reflect.String && r.Kind() == reflect.String:
+		return cmp.Compare(l.String(), r.String()), true
+	case l.Kind() == reflect.Bool && r.Kind() == reflect.Bool:
+		return compareBools(l.Bool(), r.Bool()), true
+	}
+
+	if method := l.MethodByName("Compare"); method.IsValid() {
+		signature := method.Type()
+		if signature.NumIn() == 1 && signature.NumOut() == 1 && signature.Out(0).Kind() == reflect.Int &&
+			r.Type().AssignableTo(signature.In(0)) {
+			return cmp.Compare(method.Call([]reflect.Value{r})[0].Int(), 0), true
+		}
+	}
+	return 0, false
 }
 
-func compareNumbers(left, right interface{}) int {
-	leftNum, err := toNumber(left)
-	if err != nil {
+func compareBools(left, right bool) int {
+	switch {
+	case left == right:
 		return 0
+	case left:
+		return 1
+	default:
+		return -1
 	}
+}
+
+// ErrCannotConvertToBoolean is returned when an operand of and, or, not or the
+// condition of a ternary is not a boolean
+var ErrCannotConvertToBoolean = errors.New("cannot convert to boolean")
 
-	rightNum, err := toNumber(right)
+var boolType = reflect.TypeOf(false)
+
+// booleanValue evaluates node as the operand of a boolean operator or the
+// condition of a ternary. The value must be a boolean or be converted to one by
+// the type converter, so 'true' is accepted but null, numbers and other strings
+// are not. With LenientBooleans configured, isTruthy decides instead.
+func booleanValue(state *ExpressionState, node SpelNode) (bool, error) {
+	value, err := node.GetValue(state)
 	if err != nil {
-		return 0
+		return false, err
 	}
-
-	if leftNum > rightNum {
-		return 1
-	} else if leftNum < rightNum {
-		return -1
+	if state.Configuration != nil && state.Configuration.LenientBooleans {
+		return isTruthy(value), nil
 	}
-	return 0
-}
 
-func toNumber(value interface{}) (float64, error) {
-	switch v := value.(type) {
-	case int:
-		return float64(v), nil
-	case int32:
-		return float64(v), nil
-	case int64:
-		return float64(v), nil
-	case float32:
-		return float64(v), nil
-	case float64:
-		return v, nil
-	case string:
-		return strconv.ParseFloat(v, 64)
-	default:
-		return 0, fmt.Errorf("cannot convert %T to number", value)
+	if b, ok := value.(bool); ok {
+		return b, nil
+	}
+	if isNull(value) {
+		return false, NewSpelEvaluationException(node.GetStartPosition(), TYPE_CONVERSION_ERROR, "null", "boolean").
+			WithCause(ErrCannotConvertToBoolean)
 	}
+	if converted, err := state.EvaluationContext.GetTypeConverter().ConvertValue(value, boolType); err == nil {
+		if b, ok := converted.(bool); ok {
+			return b, nil
+		}
+	}
+	return false, NewSpelEvaluationException(node.GetStartPosition(), TYPE_CONVERSION_ERROR, fmt.Sprintf("%T", value), "boolean").
+		WithCause(ErrCannotConvertToBoolean)
 }
 
 func isTruthy(value interface{}) bool {
@@ -864,12 +955,8 @@ func (op *OperatorBetween) GetValue(state *ExpressionState) (interface{}, error)
 
 	// Right side should be a list/array with exactly 2 elements [min, max]
 	rightSlice, ok := rightVal.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("between operator requires a list on the right side")
-	}
-
-	if len(rightSlice) != 2 {
-		return nil, fmt.Errorf("between operator requires exactly 2 elements in the list")
+	if !ok || len(rightSlice) != 2 {
+		return nil, NewSpelEvaluationException(op.Right.GetStartPosition(), BETWEEN_RIGHT_OPERAND_MUST_BE_TWO_ELEMENT_LIST)
 	}
 
 	min := rightSlice[0]
@@ -899,41 +986,8 @@ func (op *OperatorBetween) ToStringAST() string {
 // compareValuesForBetween compares two values and returns:
 // -1 if left < right, 0 if left == right, 1 if left > right
 func compareValuesForBetween(left, right interface{}) int {
-	// Handle nil values
-	if left == nil && right == nil {
-		return 0
-	}
-	if left == nil {
-		return -1
-	}
-	if right == nil {
-		return 1
-	}
-
-	// Try numeric comparison first
-	leftNum, leftIsNum := tryToNumber(left)
-	rightNum, rightIsNum := tryToNumber(right)
-
-	if leftIsNum && rightIsNum {
-		if leftNum > rightNum {
-			return 1
-		} else if leftNum < rightNum {
-			return -1
-		}
-		return 0
-	}
-
-	// Try string comparison
-	leftStr, leftIsStr := left.(string)
-	rightStr, rightIsStr := right.(string)
-
-	if leftIsStr && rightIsStr {
-		if leftStr > rightStr {
-			return 1
-		} else if leftStr < rightStr {
-			return -1
-		}
-		return 0
+	if comparison, ok := compareValues(left, right); ok {
+		return comparison
 	}
 
 	// Fallback: convert both to strings and compare
@@ -948,32 +1002,10 @@ func compareValuesForBetween(left, right interface{}) int {
 	return 0
 }
 
-// tryToNumber attempts to convert a value to float64, returns (value, success)
-func tryToNumber(value interface{}) (float64, bool) {
-	switch v := value.(type) {
-	case int:
-		return float64(v), true
-	case int32:
-		return float64(v), true
-	case int64:
-		return float64(v), true
-	case float32:
-		return float64(v), true
-	case float64:
-		return v, true
-	case string:
-		if num, err := strconv.ParseFloat(v, 64); err == nil {
-			return num, true
-		}
-		return 0, false
-	default:
-		return 0, false
-	}
-}
-
-// OpInc represents pre-increment operator (++expr)
+// OpInc represents the increment operator, either prefix (++expr) or postfix (expr++)
 type OpInc struct {
 	*UnaryOperator
+	Postfix bool
 }
 
 func NewOpInc(child SpelNode, startPos, endPos int) *OpInc {
@@ -982,22 +1014,23 @@ func NewOpInc(child SpelNode, startPos, endPos int) *OpInc {
 	}
 }
 
+func NewPostfixOpInc(child SpelNode, startPos, endPos int) *OpInc {
+	inc := NewOpInc(child, startPos, endPos)
+	inc.Postfix = true
+	return inc
+}
+
 func (i *OpInc) ToStringAST() string {
+	if i.Postfix {
+		return i.Child.ToStringAST() + "++"
+	}
 	return "++" + i.Child.ToStringAST()
 }
 
+// GetValue increments the operand and writes the result back to it. The prefix
+// form returns the new value and the postfix form the value before incrementing.
 func (i *OpInc) GetValue(state *ExpressionState) (interface{}, error) {
-	childValue, err := i.Child.GetValue(state)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert to number and increment
-	if num, ok := tryToNumber(childValue); ok {
-		return num + 1, nil
-	}
-
-	return nil, fmt.Errorf("cannot increment non-numeric value: %v", childValue)
+	return incrementOperand(state, i.Child, 1, i.Postfix, i.StartPos, OPERAND_NOT_INCREMENTABLE)
 }
 
 func (i *OpInc) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
@@ -1008,9 +1041,10 @@ func (i *OpInc) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
 	return NewTypedValue(value), nil
 }
 
-// OpDec represents pre-decrement operator (--expr)
+// OpDec represents the decrement operator, either prefix (--expr) or postfix (expr--)
 type OpDec struct {
 	*UnaryOperator
+	Postfix bool
 }
 
 func NewOpDec(child SpelNode, startPos, endPos int) *OpDec {
@@ -1019,22 +1053,81 @@ func NewOpDec(child SpelNode, startPos, endPos int) *OpDec {
 	}
 }
 
+func NewPostfixOpDec(child SpelNode, startPos, endPos int) *OpDec {
+	dec := NewOpDec(child, startPos, endPos)
+	dec.Postfix = true
+	return dec
+}
+
 func (d *OpDec) ToStringAST() string {
+	if d.Postfix {
+		return d.Child.ToStringAST() + "--"
+	}
 	return "--" + d.Child.ToStringAST()
 }
 
+// GetValue decrements the operand and writes the result back to it. The prefix
+// form returns the new value and the postfix form the value before decrementing.
 func (d *OpDec) GetValue(state *ExpressionState) (interface{}, error) {
-	childValue, err := d.Child.GetValue(state)
+	return incrementOperand(state, d.Child, -1, d.Postfix, d.StartPos, OPERAND_NOT_DECREMENTABLE)
+}
+
+// incrementOperand adds delta to a writable numeric operand, preserving its type.
+// The operand is resolved once, so its target and index are evaluated only once.
+// notSupported is raised for an operand that is not writable or not a number.
+func incrementOperand(state *ExpressionState, operand SpelNode, delta int64, postfix bool, position int, notSupported SpelMessage) (interface{}, error) {
+	ref, err := getValueRef(state, operand)
 	if err != nil {
 		return nil, err
 	}
+	if !ref.isWritable() {
+		return nil, NewSpelEvaluationException(position, notSupported, operand.ToStringAST())
+	}
 
-	// Convert to number and decrement
-	if num, ok := tryToNumber(childValue); ok {
-		return num - 1, nil
+	oldValue, err := ref.getValue()
+	if err != nil {
+		return nil, err
+	}
+	newValue, ok := addToNumber(oldValue, delta)
+	if !ok {
+		return nil, NewSpelEvaluationException(position, notSupported, operand.ToStringAST())
+	}
+
+	if err := ref.setValue(newValue); err != nil {
+		return nil, err
 	}
+	if postfix {
+		return oldValue, nil
+	}
+	return newValue, nil
+}
 
-	return nil, fmt.Errorf("cannot decrement non-numeric value: %v", childValue)
+// addToNumber adds delta to an integer or floating point value of any Go numeric
+// type, or to a *big.Int or *big.Float, returning a value of the same type.
+// Integers wrap around on overflow.
+func addToNumber(value interface{}, delta int64) (interface{}, bool) {
+	if isNull(value) {
+		return nil, false
+	}
+	switch v := value.(type) {
+	case *big.Int:
+		return new(big.Int).Add(v, big.NewInt(delta)), true
+	case *big.Float:
+		return new(big.Float).SetPrec(v.Prec()).Add(v, big.NewFloat(float64(delta))), true
+	}
+	v := reflect.ValueOf(value)
+	result := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		result.SetInt(v.Int() + delta)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		result.SetUint(v.Uint() + uint64(delta))
+	case reflect.Float32, reflect.Float64:
+		result.SetFloat(v.Float() + float64(delta))
+	default:
+		return nil, false
+	}
+	return result.Interface(), true
 }
 
 func (d *OpDec) GetTypedValue(state *ExpressionState) (*TypedValue, error) {
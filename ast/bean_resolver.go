@@ -0,0 +1,38 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// MapBeanResolver is a BeanResolver backed by a map of bean names to Go values.
+// Factory beans are registered separately and resolved by "&name" references.
+type MapBeanResolver struct {
+	beans map[string]interface{}
+}
+
+// NewMapBeanResolver creates a resolver holding the given beans, which may be nil
+func NewMapBeanResolver(beans map[string]interface{}) *MapBeanResolver {
+	resolver := &MapBeanResolver{beans: make(map[string]interface{}, len(beans))}
+	for name, bean := range beans {
+		resolver.RegisterBean(name, bean)
+	}
+	return resolver
+}
+
+// RegisterBean makes bean available to @name references
+func (r *MapBeanResolver) RegisterBean(name string, bean interface{}) {
+	r.beans[name] = bean
+}
+
+// RegisterFactoryBean makes the factory of the named bean available to &name references
+func (r *MapBeanResolver) RegisterFactoryBean(name string, factory interface{}) {
+	r.beans[FactoryBeanPrefix+name] = factory
+}
+
+func (r *MapBeanResolver) Resolve(context EvaluationContext, beanName string) (interface{}, error) {
+	bean, ok := r.beans[beanName]
+	if !ok {
+		return nil, fmt.Errorf("no bean named '%s' is defined", beanName)
+	}
+	return bean, nil
+}
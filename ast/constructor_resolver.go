@@ -0,0 +1,233 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ConstructorResolver creates the objects and arrays of new expressions.
+// Resolvers are consulted in order by ConstructorReference.
+type ConstructorResolver interface {
+	// Resolve returns an executor for a constructor of the named type accepting
+	// arguments of the given types, or nil if this resolver cannot construct it
+	Resolve(context EvaluationContext, typeName string, argumentTypes []reflect.Type) (ConstructorExecutor, error)
+
+	// ResolveArrayElementType returns the element type of new typeName[...]
+	// arrays, or nil if this resolver does not know the type
+	ResolveArrayElementType(context EvaluationContext, typeName string) (reflect.Type, error)
+}
+
+// ConstructorExecutor invokes a constructor found by a ConstructorResolver
+type ConstructorExecutor interface {
+	Execute(context EvaluationContext, arguments []interface{}) (interface{}, error)
+}
+
+// defaultConstructors are the Go factories for the Java types commonly
+// constructed in expressions. Overloads are listed in the order they are
+// tried for close matches.
+var defaultConstructors = map[string][]interface{}{
+	"java.lang.String": {
+		func() string { return "" },
+		func(s string) string { return s },
+	},
+	"java.lang.Integer": {
+		func(i int32) int32 { return i },
+		parseInt32,
+	},
+	"java.lang.Long": {
+		func(l int64) int64 { return l },
+		parseInt64,
+	},
+	"java.lang.Double": {
+		func(d float64) float64 { return d },
+		// Integers widen to double as in Java, without ranking every integer
+		// to float64 conversion as a close match
+		func(l int64) float64 { return float64(l) },
+		parseFloat64,
+	},
+	"java.lang.Boolean": {
+		func(b bool) bool { return b },
+		func(s string) bool { b, _ := strconv.ParseBool(s); return b },
+	},
+	"java.util.ArrayList": {
+		func() []interface{} { return []interface{}{} },
+		newArrayListWithCapacity,
+		func(values []interface{}) []interface{} { return append([]interface{}{}, values...) },
+	},
+	"java.util.HashMap": {
+		func() map[interface{}]interface{} { return map[interface{}]interface{}{} },
+		copyMap,
+	},
+	"java.util.LinkedHashMap": {
+		func() map[interface{}]interface{} { return map[interface{}]interface{}{} },
+		copyMap,
+	},
+}
+
+// primitiveArrayTypes are the element types of arrays that can always be
+// created by the standard constructor resolver
+var primitiveArrayTypes = map[string]reflect.Type{
+	"boolean":          reflect.TypeOf(false),
+	"byte":             reflect.TypeOf(int8(0)),
+	"char":             reflect.TypeOf(rune(0)),
+	"short":            reflect.TypeOf(int16(0)),
+	"int":              intType,
+	"long":             reflect.TypeOf(int64(0)),
+	"float":            reflect.TypeOf(float32(0)),
+	"double":           reflect.TypeOf(float64(0)),
+	"String":           reflect.TypeOf(""),
+	"java.lang.String": reflect.TypeOf(""),
+	"Object":           objectType,
+	"java.lang.Object": objectType,
+}
+
+// newArrayListWithCapacity mirrors new ArrayList(initialCapacity). The capacity
+// is only a hint, so no memory is reserved for it.
+func newArrayListWithCapacity(capacity int) ([]interface{}, error) {
+	if capacity < 0 {
+		return nil, fmt.Errorf("illegal capacity: %d", capacity)
+	}
+	return []interface{}{}, nil
+}
+
+func copyMap(values map[interface{}]interface{}) map[interface{}]interface{} {
+	copied := make(map[interface{}]interface{}, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
+
+// StandardConstructorResolver is a registry of Go factory functions keyed by
+// qualified type name. A name that is not registered is resolved through the
+// context's type locator, so new String('a') finds java.lang.String. Arrays
+// can be created of primitive types, String, Object and any type with a Go
+// type known to the type locator.
+type StandardConstructorResolver struct {
+	constructors     map[string][]reflect.Value
+	arrays           bool
+	unknownTypeError func(typeName string) error
+}
+
+// NewStandardConstructorResolver creates a resolver knowing the default Java
+// constructors and primitive arrays
+func NewStandardConstructorResolver() *StandardConstructorResolver {
+	resolver := NewEmptyConstructorResolver()
+	resolver.arrays = true
+	for typeName, factories := range defaultConstructors {
+		for _, factory := range factories {
+			resolver.RegisterConstructor(typeName, factory)
+		}
+	}
+	return resolver
+}
+
+// NewEmptyConstructorResolver creates a resolver that constructs nothing until
+// constructors are registered, and never creates arrays
+func NewEmptyConstructorResolver() *StandardConstructorResolver {
+	return &StandardConstructorResolver{
+		constructors: make(map[string][]reflect.Value),
+	}
+}
+
+// RegisterConstructor adds a factory function for the named type. Registering
+// several factories for a type overloads its constructor; the best match for
+// the arguments is invoked.
+func (r *StandardConstructorResolver) RegisterConstructor(typeName string, factory interface{}) error {
+	fn, ok := factory.(reflect.Value)
+	if !ok {
+		fn = reflect.ValueOf(factory)
+	}
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() || fn.Type().NumOut() == 0 {
+		return fmt.Errorf("cannot register constructor for type '%s': %T is not a factory function", typeName, factory)
+	}
+	r.constructors[typeName] = append(r.constructors[typeName], fn)
+	return nil
+}
+
+// SetUnknownTypeError makes the resolver fail with the error returned by
+// unknownTypeError for types it cannot construct, instead of leaving them to
+// the next resolver. A sandbox can use it with an empty resolver to forbid
+// construction entirely.
+func (r *StandardConstructorResolver) SetUnknownTypeError(unknownTypeError func(typeName string) error) {
+	r.unknownTypeError = unknownTypeError
+}
+
+func (r *StandardConstructorResolver) Resolve(context EvaluationContext, typeName string, argumentTypes []reflect.Type) (ConstructorExecutor, error) {
+	name, factories := r.findConstructors(context, typeName)
+	if len(factories) == 0 {
+		return nil, r.unknownType(typeName)
+	}
+
+	// Non-variadic factories are tried first, as for methods
+	candidates := append([]reflect.Value(nil), factories...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return !candidates[i].Type().IsVariadic() && candidates[j].Type().IsVariadic()
+	})
+	signatures := make([]reflect.Type, len(candidates))
+	for i, candidate := range candidates {
+		signatures[i] = candidate.Type()
+	}
+
+	index, ambiguous := selectSignature(signatures, argumentTypes, context.GetTypeConverter())
+	if ambiguous {
+		return nil, fmt.Errorf("constructor call: multiple possible constructors found on type '%s' for argument types %v",
+			name, argumentTypes)
+	}
+	if index < 0 {
+		return nil, nil
+	}
+	return &ReflectiveConstructorExecutor{typeName: name, factory: candidates[index]}, nil
+}
+
+func (r *StandardConstructorResolver) ResolveArrayElementType(context EvaluationContext, typeName string) (reflect.Type, error) {
+	if !r.arrays {
+		return nil, r.unknownType(typeName)
+	}
+	if typ, ok := primitiveArrayTypes[typeName]; ok {
+		return typ, nil
+	}
+	if locator := context.GetTypeLocator(); locator != nil {
+		if descriptor, err := locator.FindType(typeName); err == nil && descriptor.Type() != nil {
+			return descriptor.Type(), nil
+		}
+	}
+	return nil, r.unknownType(typeName)
+}
+
+// findConstructors returns the factories registered for typeName, or for the
+// qualified name the type locator resolves it to
+func (r *StandardConstructorResolver) findConstructors(context EvaluationContext, typeName string) (string, []reflect.Value) {
+	if factories, ok := r.constructors[typeName]; ok {
+		return typeName, factories
+	}
+	if locator := context.GetTypeLocator(); locator != nil {
+		if descriptor, err := locator.FindType(typeName); err == nil {
+			return descriptor.Name(), r.constructors[descriptor.Name()]
+		}
+	}
+	return typeName, nil
+}
+
+func (r *StandardConstructorResolver) unknownType(typeName string) error {
+	if r.unknownTypeError == nil {
+		return nil
+	}
+	return r.unknownTypeError(typeName)
+}
+
+// ReflectiveConstructorExecutor invokes a registered factory function
+type ReflectiveConstructorExecutor struct {
+	typeName string
+	factory  reflect.Value
+}
+
+func (e *ReflectiveConstructorExecutor) Execute(context EvaluationContext, arguments []interface{}) (interface{}, error) {
+	in, spread, err := convertArguments(context.GetTypeConverter(), e.factory.Type(), arguments)
+	if err != nil {
+		return nil, fmt.Errorf("constructor call: cannot invoke constructor of type '%s': %v", e.typeName, err)
+	}
+	return invoke(e.factory, in, spread, fmt.Sprintf("constructor call: constructor of type '%s'", e.typeName))
+}
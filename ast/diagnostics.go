@@ -0,0 +1,190 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Severity ranks a Diagnostic
+type Severity int
+
+const (
+	// SeverityError marks a problem that makes the expression invalid. The
+	// parser reports all its problems as errors.
+	SeverityError Severity = iota
+	// SeverityWarning marks a problem that does not prevent evaluation, left
+	// for tools that add their own checks to the parser's diagnostics
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Diagnostic is a problem found by ParseWithDiagnostics. Message and Inserts
+// are those of the SpelParseException that ParseExpression would return.
+type Diagnostic struct {
+	Severity Severity
+	Message  SpelMessage
+	Inserts  []interface{}
+	Position int
+	Span     SourceSpan
+}
+
+// String renders the diagnostic as "error 1:5: problem parsing right operand of '+'"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Severity, d.Span.Start, d.Message.FormatMessage(d.Inserts...))
+}
+
+// ParseWithDiagnostics parses expressionString without stopping at the first
+// error. Each error is reported as a Diagnostic and parsing resumes at the next
+// ',', ')', ']' or '}', with an ErrorNode in place of the text that could not
+// be parsed. The returned expression is never nil; it evaluates without error
+// only when there are no diagnostics.
+func (parser *SpelExpressionParser) ParseWithDiagnostics(expressionString string) (*SpelExpression, []Diagnostic) {
+	return NewInternalSpelExpressionParser(parser.Configuration).ParseWithDiagnostics(expressionString)
+}
+
+// ParseWithDiagnostics parses expressionString, collecting its errors as
+// diagnostics. See SpelExpressionParser.ParseWithDiagnostics.
+func (p *InternalSpelExpressionParser) ParseWithDiagnostics(expressionString string) (*SpelExpression, []Diagnostic) {
+	source := NewSourceText(expressionString)
+	p.recovering = true
+	p.diagnostics = nil
+	p.lastReported = nil
+	defer func() { p.recovering = false }()
+
+	var ast SpelNode
+	if err := p.tokenize(source, expressionString, 0); err != nil {
+		// The tokenizer stops at its first error, so the whole text is lost
+		p.TokenStream, p.TokenStreamLength, p.TokenStreamPointer = nil, 0, 0
+		ast = p.newErrorNode(err, 0, p.endPos)
+		p.reportError(err)
+	} else {
+		startPointer := p.TokenStreamPointer
+		root, err := p.eatTopLevelExpression()
+		if err != nil {
+			p.reportError(err)
+			p.TokenStreamPointer = p.TokenStreamLength
+			root = p.errorNodeFrom(startPointer, err)
+		} else if p.TokenStreamPointer < p.TokenStreamLength {
+			p.reportError(p.raiseMoreInput())
+		}
+		if err := p.checkNodeCount(root); err != nil {
+			p.reportError(err)
+		}
+		ast = root
+	}
+
+	sort.SliceStable(p.diagnostics, func(i, j int) bool {
+		return p.diagnostics[i].Position < p.diagnostics[j].Position
+	})
+	return p.newSpelExpression(source, expressionString, 0, ast), p.diagnostics
+}
+
+// eatRecoverable runs eat. While recovering, an error of eat is reported and
+// the tokens up to the next ',', ')', ']' or '}' are replaced by an ErrorNode.
+func (p *InternalSpelExpressionParser) eatRecoverable(eat func() (SpelNode, error)) (SpelNode, error) {
+	if !p.recovering {
+		return eat()
+	}
+	startPointer, stackSize := p.TokenStreamPointer, len(p.ConstructedNodes)
+	node, err := eat()
+	if err == nil {
+		return node, nil
+	}
+	p.reportError(err)
+	p.pendingError = nil
+	p.ConstructedNodes = p.ConstructedNodes[:stackSize]
+	p.skipTokens(true)
+	return p.errorNodeFrom(startPointer, err), nil
+}
+
+// skipTokens consumes tokens up to a closing bracket that was opened before
+// them, or up to a ',' outside brackets when atComma is set
+func (p *InternalSpelExpressionParser) skipTokens(atComma bool) {
+	depth := 0
+	for token := p.peekTokenRaw(); token != nil; token = p.peekTokenRaw() {
+		switch {
+		case isOpeningKind(token.Kind):
+			depth++
+		case isClosingKind(token.Kind):
+			if depth == 0 {
+				return
+			}
+			depth--
+		case token.Kind == COMMA && atComma && depth == 0:
+			return
+		}
+		p.takeToken()
+	}
+}
+
+// errorNodeFrom returns an ErrorNode for the tokens consumed since startPointer
+func (p *InternalSpelExpressionParser) errorNodeFrom(startPointer int, err error) *ErrorNode {
+	if p.TokenStreamPointer <= startPointer {
+		position := p.positionOf(p.peekTokenRaw())
+		return p.newErrorNode(err, position, position)
+	}
+	return p.newErrorNode(err, p.TokenStream[startPointer].StartPos, p.TokenStream[p.TokenStreamPointer-1].EndPos)
+}
+
+// newErrorNode returns an ErrorNode for the text between startPos and endPos
+// that failed to parse with err. The text shares the memory of the expression
+// string, so nested error nodes do not copy it again.
+func (p *InternalSpelExpressionParser) newErrorNode(err error, startPos, endPos int) *ErrorNode {
+	return NewErrorNode(p.source.Text(startPos, endPos), p.newDiagnostic(err), startPos, endPos)
+}
+
+// reportError adds err to the diagnostics unless it was just reported or an
+// error was already reported at its position, which happens when one problem
+// makes several enclosing expressions fail
+func (p *InternalSpelExpressionParser) reportError(err error) {
+	if err == p.lastReported {
+		return
+	}
+	p.lastReported = err
+	diagnostic := p.newDiagnostic(err)
+	for _, reported := range p.diagnostics {
+		if reported.Position == diagnostic.Position {
+			return
+		}
+	}
+	p.diagnostics = append(p.diagnostics, diagnostic)
+}
+
+// newDiagnostic returns the error diagnostic for a parse error
+func (p *InternalSpelExpressionParser) newDiagnostic(err error) Diagnostic {
+	var parseErr *SpelParseException
+	if !errors.As(err, &parseErr) {
+		parseErr = newSpelParseException(p.source, p.positionOf(p.peekTokenRaw()), NOT_EXPECTED_TOKEN, "expression", err.Error())
+	}
+	return Diagnostic{
+		Severity: SeverityError,
+		Message:  parseErr.Message,
+		Inserts:  parseErr.Inserts,
+		Position: parseErr.Position,
+		Span:     parseErr.Span,
+	}
+}
+
+// isOpeningKind reports whether kind opens a bracket closed by ')', ']' or '}'
+func isOpeningKind(kind TokenKind) bool {
+	switch kind {
+	case LPAREN, LSQUARE, LCURLY, SELECT, SELECT_FIRST, SELECT_LAST, PROJECT:
+		return true
+	}
+	return false
+}
+
+// isClosingKind reports whether kind closes a bracket
+func isClosingKind(kind TokenKind) bool {
+	return kind == RPAREN || kind == RSQUARE || kind == RCURLY
+}
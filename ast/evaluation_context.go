@@ -0,0 +1,212 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EvaluationContext provides the environment an expression is evaluated against:
+// the root object, variables, registered functions and the pluggable resolvers
+// (beans, types, conversions) that nodes consult during evaluation.
+type EvaluationContext interface {
+	// GetRootObject returns the default root context object
+	GetRootObject() *TypedValue
+
+	// SetVariable sets a named variable; a nil value removes the variable
+	SetVariable(name string, value interface{})
+
+	// LookupVariable returns the value of a named variable, or nil if not set
+	LookupVariable(name string) interface{}
+
+	// RegisterFunction registers a Go function under the given name for #name(...) calls
+	RegisterFunction(name string, function interface{}) error
+
+	// LookupFunction returns a registered function
+	LookupFunction(name string) (reflect.Value, bool)
+
+	// GetPropertyAccessors returns the accessors used to read properties, in order
+	GetPropertyAccessors() []PropertyAccessor
+
+	// GetMethodResolvers returns the resolvers used to find methods, in order
+	GetMethodResolvers() []MethodResolver
+
+	// GetConstructorResolvers returns the resolvers used for new expressions, in order
+	GetConstructorResolvers() []ConstructorResolver
+
+	// GetBeanResolver returns the resolver used for @bean references, may be nil
+	GetBeanResolver() BeanResolver
+
+	// GetTypeLocator returns the locator used for T(...) references
+	GetTypeLocator() TypeLocator
+
+	// GetTypeConverter returns the converter used to coerce values
+	GetTypeConverter() TypeConverter
+
+	// GetOperatorOverloader returns the overloader for arithmetic on operands
+	// the operators do not support, may be nil
+	GetOperatorOverloader() OperatorOverloader
+}
+
+// BeanResolver resolves a bean by name for @bean references
+type BeanResolver interface {
+	Resolve(context EvaluationContext, beanName string) (interface{}, error)
+}
+
+// TypeLocator resolves a type name, as written in T(...), to a descriptor of the
+// Go type and the static members registered for it. The standard locator
+// reports an unknown type as a TYPE_NOT_FOUND exception with position -1, which
+// the referencing node replaces by its own.
+type TypeLocator interface {
+	FindType(typeName string) (*TypeDescriptor, error)
+}
+
+// TypeConverter converts values between Go types
+type TypeConverter interface {
+	// CanConvert returns true if values of sourceType can be converted to targetType
+	CanConvert(sourceType, targetType reflect.Type) bool
+
+	// ConvertValue converts value to targetType
+	ConvertValue(value interface{}, targetType reflect.Type) (interface{}, error)
+}
+
+// StandardEvaluationContext is the default, fully configurable EvaluationContext
+type StandardEvaluationContext struct {
+	rootObject         *TypedValue
+	variables          map[string]interface{}
+	functions          map[string]reflect.Value
+	accessors          []PropertyAccessor
+	resolvers          []MethodResolver
+	constructors       []ConstructorResolver
+	beanResolver       BeanResolver
+	typeLocator        TypeLocator
+	typeConverter      TypeConverter
+	operatorOverloader OperatorOverloader
+}
+
+// NewStandardEvaluationContext creates a context with a nil root object
+func NewStandardEvaluationContext() *StandardEvaluationContext {
+	return NewStandardEvaluationContextWithRoot(nil)
+}
+
+// NewStandardEvaluationContextWithRoot creates a context with the given root object
+func NewStandardEvaluationContextWithRoot(rootObject interface{}) *StandardEvaluationContext {
+	return &StandardEvaluationContext{
+		rootObject:    NewTypedValue(rootObject),
+		variables:     make(map[string]interface{}),
+		functions:     make(map[string]reflect.Value),
+		accessors:     []PropertyAccessor{NewReflectivePropertyAccessor()},
+		resolvers:     []MethodResolver{NewReflectiveMethodResolver()},
+		constructors:  []ConstructorResolver{NewStandardConstructorResolver()},
+		typeLocator:   NewStandardTypeLocator(),
+		typeConverter: NewStandardTypeConverter(),
+	}
+}
+
+func (c *StandardEvaluationContext) GetRootObject() *TypedValue {
+	return c.rootObject
+}
+
+func (c *StandardEvaluationContext) SetRootObject(rootObject interface{}) {
+	c.rootObject = NewTypedValue(rootObject)
+}
+
+func (c *StandardEvaluationContext) SetVariable(name string, value interface{}) {
+	if value == nil {
+		delete(c.variables, name)
+		return
+	}
+	c.variables[name] = value
+}
+
+// SetVariables sets several variables at once
+func (c *StandardEvaluationContext) SetVariables(variables map[string]interface{}) {
+	for name, value := range variables {
+		c.SetVariable(name, value)
+	}
+}
+
+func (c *StandardEvaluationContext) LookupVariable(name string) interface{} {
+	return c.variables[name]
+}
+
+func (c *StandardEvaluationContext) RegisterFunction(name string, function interface{}) error {
+	fn, ok := function.(reflect.Value)
+	if !ok {
+		fn = reflect.ValueOf(function)
+	}
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return fmt.Errorf("cannot register function '%s': %T is not a function", name, function)
+	}
+	c.functions[name] = fn
+	return nil
+}
+
+func (c *StandardEvaluationContext) LookupFunction(name string) (reflect.Value, bool) {
+	fn, ok := c.functions[name]
+	return fn, ok
+}
+
+func (c *StandardEvaluationContext) GetPropertyAccessors() []PropertyAccessor {
+	return c.accessors
+}
+
+// AddPropertyAccessor adds an accessor that is consulted before the existing ones
+func (c *StandardEvaluationContext) AddPropertyAccessor(accessor PropertyAccessor) {
+	c.accessors = append([]PropertyAccessor{accessor}, c.accessors...)
+}
+
+func (c *StandardEvaluationContext) GetMethodResolvers() []MethodResolver {
+	return c.resolvers
+}
+
+// AddMethodResolver adds a resolver that is consulted before the existing ones
+func (c *StandardEvaluationContext) AddMethodResolver(resolver MethodResolver) {
+	c.resolvers = append([]MethodResolver{resolver}, c.resolvers...)
+}
+
+func (c *StandardEvaluationContext) GetConstructorResolvers() []ConstructorResolver {
+	return c.constructors
+}
+
+// AddConstructorResolver adds a resolver that is consulted before the existing ones
+func (c *StandardEvaluationContext) AddConstructorResolver(resolver ConstructorResolver) {
+	c.constructors = append([]ConstructorResolver{resolver}, c.constructors...)
+}
+
+// SetConstructorResolvers replaces the constructor resolvers. Without any
+// resolver no object or array can be constructed.
+func (c *StandardEvaluationContext) SetConstructorResolvers(resolvers ...ConstructorResolver) {
+	c.constructors = resolvers
+}
+
+func (c *StandardEvaluationContext) GetBeanResolver() BeanResolver {
+	return c.beanResolver
+}
+
+func (c *StandardEvaluationContext) SetBeanResolver(beanResolver BeanResolver) {
+	c.beanResolver = beanResolver
+}
+
+func (c *StandardEvaluationContext) GetTypeLocator() TypeLocator {
+	return c.typeLocator
+}
+
+func (c *StandardEvaluationContext) SetTypeLocator(typeLocator TypeLocator) {
+	c.typeLocator = typeLocator
+}
+
+func (c *StandardEvaluationContext) GetTypeConverter() TypeConverter {
+	return c.typeConverter
+}
+
+func (c *StandardEvaluationContext) SetTypeConverter(typeConverter TypeConverter) {
+	c.typeConverter = typeConverter
+}
+
+func (c *StandardEvaluationContext) GetOperatorOverloader() OperatorOverloader {
+	return c.operatorOverloader
+}
+
+func (c *StandardEvaluationContext) SetOperatorOverloader(operatorOverloader OperatorOverloader) {
+	c.operatorOverloader = operatorOverloader
+}
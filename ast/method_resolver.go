@@ -0,0 +1,483 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MethodResolver locates a method that can be invoked on a target object with
+// arguments of the given types. Resolvers are consulted in order by MethodReference.
+type MethodResolver interface {
+	// Resolve returns an executor for the named method, or nil if this resolver
+	// cannot find a suitable method. A nil entry in argumentTypes is a null argument.
+	Resolve(context EvaluationContext, target interface{}, name string, argumentTypes []reflect.Type) (MethodExecutor, error)
+}
+
+// MethodExecutor invokes a method found by a MethodResolver
+type MethodExecutor interface {
+	Execute(context EvaluationContext, target interface{}, arguments []interface{}) (interface{}, error)
+}
+
+// argumentsMatchKind ranks how well argument types fit a method's parameters,
+// mirroring the EXACT, CLOSE and REQUIRES_CONVERSION matches of Spring's ReflectionHelper
+type argumentsMatchKind int
+
+const (
+	noMatch argumentsMatchKind = iota
+	requiresConversion
+	closeMatch
+	exactMatch
+)
+
+// ReflectiveMethodResolver finds exported methods by case-insensitive name, so
+// "getName()" resolves to a Go GetName method. Methods shadowed through struct
+// embedding are also considered, which lets an embedding type act as an overload
+// of the embedded type's method. Exact matches win over close matches, which win
+// over matches requiring argument conversion. On a *TypeDescriptor, the value of
+// T(...), only the static functions registered for the type are candidates.
+type ReflectiveMethodResolver struct{}
+
+// NewReflectiveMethodResolver creates the default method resolver
+func NewReflectiveMethodResolver() *ReflectiveMethodResolver {
+	return &ReflectiveMethodResolver{}
+}
+
+func (r *ReflectiveMethodResolver) Resolve(context EvaluationContext, target interface{}, name string, argumentTypes []reflect.Type) (MethodExecutor, error) {
+	if target == nil {
+		return nil, nil
+	}
+
+	var candidates []*ReflectiveMethodExecutor
+	if descriptor, ok := target.(*TypeDescriptor); ok {
+		candidates = descriptor.findFunctions(name)
+	} else {
+		candidates = findMethods(reflect.TypeOf(target), name)
+	}
+
+	signatures := make([]reflect.Type, len(candidates))
+	for i, candidate := range candidates {
+		signatures[i] = candidate.signature
+	}
+	index, ambiguous := selectSignature(signatures, argumentTypes, context.GetTypeConverter())
+	if ambiguous {
+		return nil, NewSpelEvaluationException(positionUnknown, MULTIPLE_POSSIBLE_METHODS, name)
+	}
+	if index < 0 {
+		return nil, nil
+	}
+	return candidates[index], nil
+}
+
+// selectSignature returns the index of the function type best matching
+// argumentTypes, or -1 if none matches. An exact match wins immediately,
+// otherwise the first close match is taken. A match requiring conversion is
+// only taken if it is the only one; ambiguous is true if there are several.
+func selectSignature(signatures []reflect.Type, argumentTypes []reflect.Type, converter TypeConverter) (index int, ambiguous bool) {
+	closest, conversion := -1, -1
+	multipleConversions := false
+	for i, signature := range signatures {
+		switch matchArguments(signature, argumentTypes, converter) {
+		case exactMatch:
+			return i, false
+		case closeMatch:
+			if closest < 0 {
+				closest = i
+			}
+		case requiresConversion:
+			if conversion >= 0 {
+				multipleConversions = true
+			} else {
+				conversion = i
+			}
+		}
+	}
+
+	if closest >= 0 {
+		return closest, false
+	}
+	if multipleConversions {
+		return -1, true
+	}
+	return conversion, false
+}
+
+// ReflectiveMethodExecutor invokes a Go method, reached from the target through
+// a path of embedded fields, or a static function of a *TypeDescriptor target
+type ReflectiveMethodExecutor struct {
+	name      string
+	path      []int
+	signature reflect.Type
+	static    bool
+}
+
+func (e *ReflectiveMethodExecutor) Execute(context EvaluationContext, target interface{}, arguments []interface{}) (interface{}, error) {
+	method, err := e.method(target)
+	if err != nil {
+		return nil, err
+	}
+
+	in, spread, err := convertArguments(context.GetTypeConverter(), e.signature, arguments)
+	if err != nil {
+		return nil, fmt.Errorf("method call: cannot invoke method '%s': %v", e.name, err)
+	}
+
+	return invoke(method, in, spread, fmt.Sprintf("method call: method '%s'", e.name))
+}
+
+// method walks the embedded field path from target and returns the bound method
+func (e *ReflectiveMethodExecutor) method(target interface{}) (reflect.Value, error) {
+	if e.static {
+		descriptor, ok := target.(*TypeDescriptor)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("method call: static method '%s' cannot be invoked on type '%T'", e.name, target)
+		}
+		function, ok := descriptor.lookupFunction(e.name, e.signature)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("method call: static method '%s' cannot be found on type '%s'", e.name, descriptor.Name())
+		}
+		return function, nil
+	}
+
+	receiver := reflect.ValueOf(target)
+	for _, index := range e.path {
+		value, ok := unwrapValue(receiver)
+		if !ok || value.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("method call: cannot invoke method '%s' through a nil embedded field", e.name)
+		}
+		receiver = value.Field(index)
+	}
+
+	if receiver.Kind() != reflect.Ptr && receiver.Kind() != reflect.Interface {
+		receiver = addressableCopy(receiver)
+	} else if receiver.IsNil() {
+		return reflect.Value{}, fmt.Errorf("method call: cannot invoke method '%s' on a nil receiver", e.name)
+	}
+
+	method := receiver.MethodByName(e.name)
+	if !method.IsValid() {
+		return reflect.Value{}, fmt.Errorf("method call: method '%s' cannot be found on type '%v'", e.name, receiver.Type())
+	}
+	return method, nil
+}
+
+// findMethods collects the methods of typ matching name, followed by the
+// methods of the same name on embedded fields. Methods whose signature is
+// already present at a shallower depth are promoted duplicates and skipped.
+// Non-variadic methods are ordered before variadic ones, as in Spring.
+func findMethods(typ reflect.Type, name string) []*ReflectiveMethodExecutor {
+	var candidates []*ReflectiveMethodExecutor
+	collectMethods(typ, name, nil, &candidates, map[reflect.Type]bool{})
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return !candidates[i].signature.IsVariadic() && candidates[j].signature.IsVariadic()
+	})
+	return candidates
+}
+
+func collectMethods(typ reflect.Type, name string, path []int, candidates *[]*ReflectiveMethodExecutor, visited map[reflect.Type]bool) {
+	if visited[typ] {
+		return
+	}
+	visited[typ] = true
+
+	// Methods with pointer receivers are reachable through an addressable copy
+	methodType := typ
+	if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+		methodType = reflect.PtrTo(typ)
+	}
+	for i := 0; i < methodType.NumMethod(); i++ {
+		method := methodType.Method(i)
+		if !strings.EqualFold(method.Name, name) {
+			continue
+		}
+		signature := methodSignature(methodType, method)
+		if hasSignature(*candidates, signature) {
+			continue
+		}
+		*candidates = append(*candidates, &ReflectiveMethodExecutor{
+			name:      method.Name,
+			path:      path,
+			signature: signature,
+		})
+	}
+
+	structType := typ
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.Anonymous || !field.IsExported() {
+			continue
+		}
+		fieldPath := append(append([]int(nil), path...), i)
+		collectMethods(field.Type, name, fieldPath, candidates, visited)
+	}
+}
+
+// methodSignature returns the function type of a method without its receiver
+func methodSignature(typ reflect.Type, method reflect.Method) reflect.Type {
+	if typ.Kind() == reflect.Interface {
+		return method.Type
+	}
+	in := make([]reflect.Type, 0, method.Type.NumIn()-1)
+	for i := 1; i < method.Type.NumIn(); i++ {
+		in = append(in, method.Type.In(i))
+	}
+	out := make([]reflect.Type, 0, method.Type.NumOut())
+	for i := 0; i < method.Type.NumOut(); i++ {
+		out = append(out, method.Type.Out(i))
+	}
+	return reflect.FuncOf(in, out, method.Type.IsVariadic())
+}
+
+func hasSignature(candidates []*ReflectiveMethodExecutor, signature reflect.Type) bool {
+	for _, candidate := range candidates {
+		if candidate.signature == signature {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArguments ranks argumentTypes against the parameters of a function type.
+// The match is only as good as the worst matching argument.
+func matchArguments(signature reflect.Type, argumentTypes []reflect.Type, converter TypeConverter) argumentsMatchKind {
+	paramCount := signature.NumIn()
+	if !signature.IsVariadic() {
+		if len(argumentTypes) != paramCount {
+			return noMatch
+		}
+		match := exactMatch
+		for i, argumentType := range argumentTypes {
+			match = minMatch(match, matchArgument(argumentType, signature.In(i), converter))
+		}
+		return match
+	}
+
+	fixedCount := paramCount - 1
+	if len(argumentTypes) < fixedCount {
+		return noMatch
+	}
+	match := exactMatch
+	for i := 0; i < fixedCount; i++ {
+		match = minMatch(match, matchArgument(argumentTypes[i], signature.In(i), converter))
+	}
+
+	// A single argument that already is the variadic slice is passed as is
+	sliceType := signature.In(fixedCount)
+	if len(argumentTypes) == paramCount {
+		if sliceMatch := matchArgument(argumentTypes[fixedCount], sliceType, converter); sliceMatch >= closeMatch {
+			return minMatch(match, sliceMatch)
+		}
+	}
+	for _, argumentType := range argumentTypes[fixedCount:] {
+		match = minMatch(match, matchArgument(argumentType, sliceType.Elem(), converter))
+	}
+	return match
+}
+
+// matchArgument ranks a single argument type against a parameter type
+func matchArgument(argumentType, paramType reflect.Type, converter TypeConverter) argumentsMatchKind {
+	switch {
+	case argumentType == nil:
+		if isNillableType(paramType) {
+			return closeMatch
+		}
+		return noMatch
+	case argumentType == paramType:
+		return exactMatch
+	case argumentType.AssignableTo(paramType), isWideningConversion(argumentType, paramType):
+		return closeMatch
+	case converter != nil && converter.CanConvert(argumentType, paramType):
+		return requiresConversion
+	default:
+		return noMatch
+	}
+}
+
+func minMatch(a, b argumentsMatchKind) argumentsMatchKind {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// isWideningConversion reports a lossless conversion within the signed integer,
+// unsigned integer or floating point families, such as int32 to int
+func isWideningConversion(sourceType, targetType reflect.Type) bool {
+	family := func(kind reflect.Kind) int {
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return 1
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return 2
+		case reflect.Float32, reflect.Float64:
+			return 3
+		default:
+			return 0
+		}
+	}
+	sourceFamily := family(sourceType.Kind())
+	return sourceFamily != 0 && sourceFamily == family(targetType.Kind()) &&
+		targetType.Bits() >= sourceType.Bits()
+}
+
+// convertArguments converts arguments to the parameter types of a function type.
+// spread is true when the last argument is the variadic slice itself and the
+// function must be invoked with CallSlice. A panic in the converter is
+// returned as an error.
+func convertArguments(converter TypeConverter, signature reflect.Type, arguments []interface{}) (in []reflect.Value, spread bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			in, spread, err = nil, false, fmt.Errorf("argument conversion panicked: %v", r)
+		}
+	}()
+
+	paramCount := signature.NumIn()
+	fixedCount := paramCount
+	if signature.IsVariadic() {
+		fixedCount--
+	}
+	if !hasValidArity(signature, len(arguments)) {
+		return nil, false, fmt.Errorf("expected %d arguments but got %d", fixedCount, len(arguments))
+	}
+
+	in = make([]reflect.Value, 0, len(arguments))
+	for i := 0; i < fixedCount; i++ {
+		value, err := convertArgument(converter, arguments[i], signature.In(i))
+		if err != nil {
+			return nil, false, err
+		}
+		in = append(in, value)
+	}
+	if !signature.IsVariadic() {
+		return in, false, nil
+	}
+
+	sliceType := signature.In(fixedCount)
+	if len(arguments) == paramCount && arguments[fixedCount] != nil &&
+		reflect.TypeOf(arguments[fixedCount]).AssignableTo(sliceType) {
+		return append(in, reflect.ValueOf(arguments[fixedCount])), true, nil
+	}
+	for _, argument := range arguments[fixedCount:] {
+		value, err := convertArgument(converter, argument, sliceType.Elem())
+		if err != nil {
+			return nil, false, err
+		}
+		in = append(in, value)
+	}
+	return in, false, nil
+}
+
+// hasValidArity reports whether a function type accepts argumentCount arguments
+func hasValidArity(signature reflect.Type, argumentCount int) bool {
+	if signature.IsVariadic() {
+		return argumentCount >= signature.NumIn()-1
+	}
+	return argumentCount == signature.NumIn()
+}
+
+// convertArgument converts a single argument to the given parameter type. A
+// floating point argument with a fractional part is not truncated to an
+// integer parameter.
+func convertArgument(converter TypeConverter, argument interface{}, paramType reflect.Type) (reflect.Value, error) {
+	if argument != nil && reflect.TypeOf(argument).AssignableTo(paramType) {
+		return reflect.ValueOf(argument), nil
+	}
+	if isIntegerType(paramType) && hasFraction(argument) {
+		return reflect.Value{}, fmt.Errorf("cannot convert %v to %v without losing precision", argument, paramType)
+	}
+	if converter == nil {
+		converter = NewStandardTypeConverter()
+	}
+
+	converted, err := converter.ConvertValue(argument, paramType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if converted == nil {
+		return reflect.Zero(paramType), nil
+	}
+	value := reflect.ValueOf(converted)
+	if !value.Type().AssignableTo(paramType) {
+		if !value.Type().ConvertibleTo(paramType) {
+			return reflect.Value{}, fmt.Errorf("cannot convert %T to %v", converted, paramType)
+		}
+		value = value.Convert(paramType)
+	}
+	return value, nil
+}
+
+// isIntegerType reports whether typ is a Go integer type or *big.Int
+func isIntegerType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return typ == bigIntType
+}
+
+// hasFraction reports whether value is a floating point number that is not a
+// whole number, including NaN and the infinities
+func hasFraction(value interface{}) bool {
+	switch v := value.(type) {
+	case float32:
+		return hasFraction(float64(v))
+	case float64:
+		return math.IsInf(v, 0) || v != math.Trunc(v)
+	case *big.Float:
+		return v != nil && !v.IsInt()
+	}
+	return false
+}
+
+// invoke calls fn with the converted arguments, turning a panic in the called
+// code into an error prefixed with description
+func invoke(fn reflect.Value, in []reflect.Value, spread bool, description string) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%s panicked: %v", description, r)
+		}
+	}()
+	if spread {
+		return invocationResult(fn.CallSlice(in))
+	}
+	return invocationResult(fn.Call(in))
+}
+
+// invocationResult maps the results of a method call to a value and an error.
+// A trailing error result is returned as the error.
+func invocationResult(results []reflect.Value) (interface{}, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+	last := results[len(results)-1]
+	if last.Type() == errorType {
+		if !last.IsNil() {
+			return nil, last.Interface().(error)
+		}
+		if len(results) == 1 {
+			return nil, nil
+		}
+	}
+	return valueToInterface(results[0]), nil
+}
+
+// argumentTypes returns the dynamic types of arguments, nil for null arguments
+func argumentTypes(arguments []interface{}) []reflect.Type {
+	types := make([]reflect.Type, len(arguments))
+	for i, argument := range arguments {
+		if argument != nil {
+			types[i] = reflect.TypeOf(argument)
+		}
+	}
+	return types
+}
@@ -0,0 +1,556 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrDivisionByZero is returned for integer, BigInteger and BigDecimal division
+// or remainder by zero, where Java throws an ArithmeticException. Floating point
+// division by zero gives an infinity or NaN instead.
+var ErrDivisionByZero = errors.New("/ by zero")
+
+// numericRank orders the number types by SpEL's binary numeric promotion: both
+// operands of an arithmetic or relational operator are converted to the type of
+// the higher ranked one. The Java types map to Go as int -> int32, long -> int64,
+// float -> float32, double -> float64, BigInteger -> *big.Int and BigDecimal ->
+// *big.Float. Smaller integers (byte, short, char) are promoted to int as in Java.
+// A Go int is a 64-bit integer ranked between int and long, so values of int
+// fields and variables keep their type when combined with int literals.
+type numericRank int
+
+const (
+	notNumeric numericRank = iota
+	intRank
+	goIntRank
+	longRank
+	bigIntegerRank
+	floatRank
+	doubleRank
+	bigDecimalRank
+)
+
+// bigDecimalPrecision is the mantissa precision in bits of the *big.Float values
+// other numbers are promoted to
+const bigDecimalPrecision = 128
+
+// numericRankOf returns the promotion rank of a number, or notNumeric for
+// anything else. Named types are ranked by their underlying kind.
+func numericRankOf(value interface{}) numericRank {
+	switch v := value.(type) {
+	case nil:
+		return notNumeric
+	case *big.Int:
+		if v == nil {
+			return notNumeric
+		}
+		return bigIntegerRank
+	case *big.Float:
+		if v == nil {
+			return notNumeric
+		}
+		return bigDecimalRank
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return intRank
+	case reflect.Int:
+		return goIntRank
+	case reflect.Int64, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		return longRank
+	case reflect.Float32:
+		return floatRank
+	case reflect.Float64:
+		return doubleRank
+	default:
+		return notNumeric
+	}
+}
+
+// isNumber reports whether a value takes part in numeric promotion
+func isNumber(value interface{}) bool {
+	return numericRankOf(value) != notNumeric
+}
+
+// promotedRank returns the rank both operands are promoted to, or notNumeric
+// if either operand is not a number
+func promotedRank(left, right interface{}) numericRank {
+	leftRank, rightRank := numericRankOf(left), numericRankOf(right)
+	if leftRank == notNumeric || rightRank == notNumeric {
+		return notNumeric
+	}
+	return max(leftRank, rightRank)
+}
+
+// promote converts a number to the type of the given rank
+func promote(value interface{}, rank numericRank) (interface{}, error) {
+	switch rank {
+	case bigDecimalRank:
+		return toBigFloat(value)
+	case doubleRank:
+		return toFloat64(value), nil
+	case floatRank:
+		return float32(toFloat64(value)), nil
+	case bigIntegerRank:
+		return toBigInt(value), nil
+	case longRank:
+		return toInt64(value), nil
+	case goIntRank:
+		return int(toInt64(value)), nil
+	case intRank:
+		return int32(toInt64(value)), nil
+	default:
+		return nil, fmt.Errorf("%T is not a number", value)
+	}
+}
+
+// toInt64 converts a number to int64, truncating like Java's longValue()
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.Int64()
+	case *big.Float:
+		i, _ := v.Int64()
+		return i
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return floatToInt64(v.Float())
+	default:
+		return 0
+	}
+}
+
+// toFloat64 converts a number to float64, like Java's doubleValue()
+func toFloat64(value interface{}) float64 {
+	switch v := value.(type) {
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(v).Float64()
+		return f
+	case *big.Float:
+		f, _ := v.Float64()
+		return f
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return 0
+	}
+}
+
+// toBigInt converts an integer or BigInteger to *big.Int
+func toBigInt(value interface{}) *big.Int {
+	switch v := value.(type) {
+	case *big.Int:
+		return v
+	case *big.Float:
+		i, _ := v.Int(nil)
+		return i
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint())
+	default:
+		return big.NewInt(toInt64(value))
+	}
+}
+
+// toBigFloat converts a number to *big.Float. Floating point values are
+// converted through their shortest decimal representation, like Java's
+// new BigDecimal(Double.toString(d)), so 0.1 stays 0.1.
+func toBigFloat(value interface{}) (*big.Float, error) {
+	switch v := value.(type) {
+	case *big.Float:
+		return v, nil
+	case *big.Int:
+		return new(big.Float).SetPrec(bigDecimalPrecision).SetInt(v), nil
+	}
+
+	result := new(big.Float).SetPrec(bigDecimalPrecision)
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("cannot convert %v to BigDecimal", f)
+		}
+		result.SetString(strconv.FormatFloat(f, 'g', -1, v.Type().Bits()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		result.SetUint64(v.Uint())
+	default:
+		result.SetInt64(toInt64(value))
+	}
+	return result, nil
+}
+
+// floatToInt64 converts like Java's (long) cast: NaN becomes 0 and values out
+// of range saturate
+func floatToInt64(f float64) int64 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(f)
+	}
+}
+
+// floatToInt32 converts like Java's (int) cast
+func floatToInt32(f float64) int32 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt32:
+		return math.MaxInt32
+	case f <= math.MinInt32:
+		return math.MinInt32
+	default:
+		return int32(f)
+	}
+}
+
+// arithmetic applies one of the operators + - * / % to two numbers after binary
+// numeric promotion. As in Java, integer results wrap around on overflow,
+// integer division truncates towards zero and the remainder takes the sign of
+// the dividend. Division by zero fails with ErrDivisionByZero unless one of the
+// operands is a float or double, for which operations follow IEEE 754.
+func arithmetic(operator rune, left, right interface{}) (interface{}, error) {
+	switch promotedRank(left, right) {
+	case bigDecimalRank:
+		a, err := toBigFloat(left)
+		if err != nil {
+			return nil, err
+		}
+		b, err := toBigFloat(right)
+		if err != nil {
+			return nil, err
+		}
+		return bigFloatArithmetic(operator, a, b)
+	case doubleRank:
+		return floatArithmetic(operator, toFloat64(left), toFloat64(right))
+	case floatRank:
+		return floatArithmetic(operator, float32(toFloat64(left)), float32(toFloat64(right)))
+	case bigIntegerRank:
+		return bigIntArithmetic(operator, toBigInt(left), toBigInt(right))
+	case longRank:
+		return integerArithmetic(operator, toInt64(left), toInt64(right))
+	case goIntRank:
+		return integerArithmetic(operator, int(toInt64(left)), int(toInt64(right)))
+	case intRank:
+		return integerArithmetic(operator, int32(toInt64(left)), int32(toInt64(right)))
+	default:
+		return nil, fmt.Errorf("operator '%c' is not supported between objects of type '%T' and '%T'", operator, left, right)
+	}
+}
+
+func integerArithmetic[T int32 | int | int64](operator rune, a, b T) (interface{}, error) {
+	switch operator {
+	case '+':
+		return a + b, nil
+	case '-':
+		return a - b, nil
+	case '*':
+		return a * b, nil
+	case '/':
+		if b == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return a / b, nil
+	case '%':
+		if b == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return a % b, nil
+	}
+	return nil, fmt.Errorf("unknown arithmetic operator '%c'", operator)
+}
+
+func floatArithmetic[T float32 | float64](operator rune, a, b T) (interface{}, error) {
+	switch operator {
+	case '+':
+		return a + b, nil
+	case '-':
+		return a - b, nil
+	case '*':
+		return a * b, nil
+	case '/':
+		return a / b, nil
+	case '%':
+		return T(math.Mod(float64(a), float64(b))), nil
+	}
+	return nil, fmt.Errorf("unknown arithmetic operator '%c'", operator)
+}
+
+func bigIntArithmetic(operator rune, a, b *big.Int) (interface{}, error) {
+	result := new(big.Int)
+	switch operator {
+	case '+':
+		return result.Add(a, b), nil
+	case '-':
+		return result.Sub(a, b), nil
+	case '*':
+		return result.Mul(a, b), nil
+	case '/':
+		if b.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return result.Quo(a, b), nil
+	case '%':
+		if b.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return result.Rem(a, b), nil
+	}
+	return nil, fmt.Errorf("unknown arithmetic operator '%c'", operator)
+}
+
+func bigFloatArithmetic(operator rune, a, b *big.Float) (interface{}, error) {
+	result := new(big.Float).SetPrec(max(a.Prec(), b.Prec()))
+	switch operator {
+	case '+':
+		return result.Add(a, b), nil
+	case '-':
+		return result.Sub(a, b), nil
+	case '*':
+		return result.Mul(a, b), nil
+	case '/':
+		if b.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		return result.Quo(a, b), nil
+	case '%':
+		if b.Sign() == 0 {
+			return nil, ErrDivisionByZero
+		}
+		// BigDecimal.remainder: a - b * trunc(a / b)
+		quotient, _ := new(big.Float).SetPrec(result.Prec()).Quo(a, b).Int(nil)
+		truncated := new(big.Float).SetPrec(result.Prec()).SetInt(quotient)
+		return result.Sub(a, truncated.Mul(truncated, b)), nil
+	}
+	return nil, fmt.Errorf("unknown arithmetic operator '%c'", operator)
+}
+
+// power raises a number to a power following SpEL's OperatorPower: BigDecimal and
+// BigInteger bases are raised to the int value of the exponent, double and float
+// operands give a double, and otherwise the result is an int unless an operand
+// is a long or the result does not fit into an int.
+func power(base, exponent interface{}) (interface{}, error) {
+	baseRank, exponentRank := numericRankOf(base), numericRankOf(exponent)
+	if baseRank == notNumeric || exponentRank == notNumeric {
+		return nil, fmt.Errorf("operator '^' is not supported between objects of type '%T' and '%T'", base, exponent)
+	}
+
+	switch {
+	case baseRank == bigDecimalRank:
+		return bigFloatPower(base.(*big.Float), int32(toInt64(exponent)))
+	case baseRank == bigIntegerRank:
+		n := int32(toInt64(exponent))
+		if n < 0 {
+			return nil, fmt.Errorf("negative exponent %d for BigInteger power", n)
+		}
+		return new(big.Int).Exp(base.(*big.Int), big.NewInt(int64(n)), nil), nil
+	case baseRank == doubleRank || exponentRank == doubleRank, baseRank == floatRank || exponentRank == floatRank:
+		return math.Pow(toFloat64(base), toFloat64(exponent)), nil
+	}
+
+	d := math.Pow(toFloat64(base), toFloat64(exponent))
+	switch {
+	case baseRank == longRank || exponentRank == longRank:
+		return floatToInt64(d), nil
+	case baseRank == goIntRank || exponentRank == goIntRank:
+		return int(floatToInt64(d)), nil
+	case d > math.MaxInt32:
+		return floatToInt64(d), nil
+	default:
+		return floatToInt32(d), nil
+	}
+}
+
+// bigFloatPower computes BigDecimal.pow(n) by repeated squaring
+func bigFloatPower(base *big.Float, n int32) (*big.Float, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("negative exponent %d for BigDecimal power", n)
+	}
+	result := new(big.Float).SetPrec(base.Prec()).SetInt64(1)
+	square := new(big.Float).Copy(base)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result.Mul(result, square)
+		}
+		square.Mul(square, square)
+	}
+	return result, nil
+}
+
+// negateNumber negates a number. byte and short operands are promoted to int
+// and int negation wraps around, so -Integer.MIN_VALUE is Integer.MIN_VALUE.
+func negateNumber(operand interface{}) (interface{}, error) {
+	switch numericRankOf(operand) {
+	case bigDecimalRank:
+		return new(big.Float).Neg(operand.(*big.Float)), nil
+	case bigIntegerRank:
+		return new(big.Int).Neg(operand.(*big.Int)), nil
+	case doubleRank:
+		return -toFloat64(operand), nil
+	case floatRank:
+		return -float32(toFloat64(operand)), nil
+	case longRank:
+		return -toInt64(operand), nil
+	case goIntRank:
+		return -int(toInt64(operand)), nil
+	case intRank:
+		return -int32(toInt64(operand)), nil
+	default:
+		return nil, fmt.Errorf("cannot negate non-numeric value: %v", operand)
+	}
+}
+
+// compareNumbers compares two numbers after binary numeric promotion and
+// returns -1, 0 or +1. ordered is false if either operand is not a number or
+// is NaN, in which case every relational operator yields false, as in Java.
+func compareNumbers(left, right interface{}) (comparison int, ordered bool) {
+	switch promotedRank(left, right) {
+	case bigDecimalRank:
+		a, err := toBigFloat(left)
+		if err != nil {
+			return 0, false
+		}
+		b, err := toBigFloat(right)
+		if err != nil {
+			return 0, false
+		}
+		return a.Cmp(b), true
+	case doubleRank, floatRank:
+		a, b := toFloat64(left), toFloat64(right)
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return 0, false
+		}
+		return compareOrdered(a, b), true
+	case bigIntegerRank:
+		return toBigInt(left).Cmp(toBigInt(right)), true
+	case longRank, goIntRank, intRank:
+		if numericRankOf(left) == longRank && isUnsigned(left) || numericRankOf(right) == longRank && isUnsigned(right) {
+			return toBigInt(left).Cmp(toBigInt(right)), true
+		}
+		return compareOrdered(toInt64(left), toInt64(right)), true
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isUnsigned(value interface{}) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// numbersEqual reports whether two numbers are equal after binary numeric
+// promotion, so 1 == 1L == 1.0. NaN is not equal to anything.
+func numbersEqual(left, right interface{}) bool {
+	comparison, ordered := compareNumbers(left, right)
+	return ordered && comparison == 0
+}
+
+// formatNumber formats a number the way Java's toString() does, so that string
+// concatenation gives the same text as in Java: doubles always have a fraction
+// or an exponent (1.0, 1.0E10) and infinities are spelled out.
+func formatNumber(value interface{}) string {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case *big.Float:
+		return v.Text('f', -1)
+	}
+
+	switch numericRankOf(value) {
+	case doubleRank:
+		return formatFloat(toFloat64(value), 64)
+	case floatRank:
+		return formatFloat(toFloat64(value), 32)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+
+	abs := math.Abs(f)
+	if abs == 0 || abs >= 1e-3 && abs < 1e7 {
+		text := strconv.FormatFloat(f, 'f', -1, bitSize)
+		if !strings.Contains(text, ".") {
+			text += ".0"
+		}
+		return text
+	}
+
+	// Go formats 1e10 as 1e+10, Java as 1.0E10
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, bitSize), "e")
+	if !strings.Contains(mantissa, ".") {
+		mantissa += ".0"
+	}
+	exponent = strings.TrimPrefix(exponent, "+")
+	negative := strings.HasPrefix(exponent, "-")
+	exponent = strings.TrimLeft(strings.TrimPrefix(exponent, "-"), "0")
+	if negative {
+		exponent = "-" + exponent
+	}
+	return mantissa + "E" + exponent
+}
+
+// toSpelString converts an operand of string concatenation to text: null is
+// "null" and numbers are formatted as in Java
+func toSpelString(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	if isNumber(value) {
+		return formatNumber(value)
+	}
+	return fmt.Sprint(value)
+}
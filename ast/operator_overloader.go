@@ -0,0 +1,45 @@
+package ast
+
+// Operation identifies an arithmetic operator that can be overloaded
+type Operation int
+
+const (
+	OperationAdd Operation = iota
+	OperationSubtract
+	OperationMultiply
+	OperationDivide
+	OperationModulus
+	OperationPower
+)
+
+func (o Operation) String() string {
+	switch o {
+	case OperationAdd:
+		return "+"
+	case OperationSubtract:
+		return "-"
+	case OperationMultiply:
+		return "*"
+	case OperationDivide:
+		return "/"
+	case OperationModulus:
+		return "%"
+	case OperationPower:
+		return "^"
+	default:
+		return "?"
+	}
+}
+
+// OperatorOverloader extends the arithmetic operators to operands they do not
+// support natively, such as user types or a string and a boolean. It is only
+// consulted after the built-in number, string concatenation and text repetition
+// rules did not apply.
+type OperatorOverloader interface {
+	// OverridesOperation returns true if Operate implements the operation for
+	// the operands. right is nil for a unary minus.
+	OverridesOperation(operation Operation, left, right interface{}) (bool, error)
+
+	// Operate performs the operation on the operands
+	Operate(operation Operation, left, right interface{}) (interface{}, error)
+}
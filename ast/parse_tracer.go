@@ -0,0 +1,83 @@
+package ast
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// ParseTracer receives events while expressions are parsed. Set it as the
+// Tracer of a SpelParserConfiguration to debug the parser; when the tracer is
+// nil parsing has no side effects.
+type ParseTracer interface {
+	// TokensProduced is called with the token stream of each expression once
+	// it has been tokenized, before it is parsed
+	TokensProduced(expressionString string, tokens []*Token)
+	// NodeConstructed is called for each node of the tree once the whole
+	// expression has parsed, children before their parent, rather than as the
+	// nodes are constructed. Nothing is reported for an expression that fails
+	// to parse, except by ParseWithDiagnostics, which reports the recovered
+	// tree including its ErrorNodes.
+	NodeConstructed(expressionString string, node SpelNode)
+}
+
+// slogParseTracer logs parse events to a slog.Logger
+type slogParseTracer struct {
+	logger *slog.Logger
+}
+
+// NewSlogParseTracer returns a ParseTracer that logs each event to logger at
+// debug level, with the tokens or the node type and positions as attributes.
+// The attributes are only built when the logger is enabled for debug.
+func NewSlogParseTracer(logger *slog.Logger) ParseTracer {
+	return &slogParseTracer{logger: logger}
+}
+
+func (t *slogParseTracer) TokensProduced(expressionString string, tokens []*Token) {
+	ctx := context.Background()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	texts := make([]string, len(tokens))
+	for i, token := range tokens {
+		texts[i] = token.String()
+	}
+	t.logger.LogAttrs(ctx, slog.LevelDebug, "tokens produced",
+		slog.String("expression", expressionString),
+		slog.Int("count", len(tokens)),
+		slog.Any("tokens", texts))
+}
+
+func (t *slogParseTracer) NodeConstructed(expressionString string, node SpelNode) {
+	ctx := context.Background()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	t.logger.LogAttrs(ctx, slog.LevelDebug, "node constructed",
+		slog.String("expression", expressionString),
+		slog.String("type", nodeTypeName(node)),
+		slog.String("ast", node.ToStringAST()),
+		slog.Int("start", node.GetStartPosition()),
+		slog.Int("end", node.GetEndPosition()))
+}
+
+// traceNodes reports node and its descendants to tracer, children first
+func traceNodes(tracer ParseTracer, expressionString string, node SpelNode) {
+	if node == nil {
+		return
+	}
+	for _, child := range node.GetChildren() {
+		traceNodes(tracer, expressionString, child)
+	}
+	tracer.NodeConstructed(expressionString, node)
+}
+
+// nodeTypeName returns the type name of node without package or pointer, such as OpPlus
+func nodeTypeName(node SpelNode) string {
+	nodeType := reflect.TypeOf(node).String()
+	if idx := strings.LastIndex(nodeType, "."); idx >= 0 {
+		nodeType = nodeType[idx+1:]
+	}
+	return strings.TrimPrefix(nodeType, "*")
+}
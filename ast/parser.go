@@ -1,8 +1,11 @@
 package ast
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
+	"unicode/utf8"
 )
 
 // InternalSpelExpressionParser handles the parsing of SpEL expressions
@@ -13,6 +16,23 @@ type InternalSpelExpressionParser struct {
 	TokenStreamLength  int
 	TokenStreamPointer int
 	ConstructedNodes   []SpelNode
+
+	// pendingError is the first error of a maybeEat method, returned by eatStartNode
+	pendingError error
+	// source locates lines and columns in ExpressionString
+	source *SourceText
+	// endPos is the position just after the parsed text, which ends before
+	// ExpressionString for an expression of a template
+	endPos int
+	// recovering is set by ParseWithDiagnostics, which collects errors in
+	// diagnostics and parses on instead of failing
+	recovering  bool
+	diagnostics []Diagnostic
+	// lastReported is the last error added to diagnostics, which is not added
+	// again while it propagates to an enclosing expression
+	lastReported error
+	// nestingDepth counts the nested expressions being parsed, see enterNested
+	nestingDepth int
 }
 
 // NewInternalSpelExpressionParser creates a new parser instance
@@ -23,133 +43,139 @@ func NewInternalSpelExpressionParser(config *SpelParserConfiguration) *InternalS
 	}
 }
 
-// DoParseExpression parses a SpEL expression string into an AST with debug output
+// DoParseExpression parses a SpEL expression string into an AST. It is the same
+// as ParseExpression; set SpelParserConfiguration.Tracer to observe parsing.
 func (p *InternalSpelExpressionParser) DoParseExpression(expressionString string) (*SpelExpression, error) {
-	p.ExpressionString = expressionString
-
-	// Check expression length
-	if len(expressionString) > p.Configuration.MaximumExpressionLength {
-		return nil, fmt.Errorf("expression exceeds maximum length of %d", p.Configuration.MaximumExpressionLength)
-	}
-
-	// Tokenize the expression
-	tokenizer := NewTokenizer(expressionString)
-	tokens, err := tokenizer.Process()
-	if err != nil {
-		return nil, fmt.Errorf("tokenization failed: %v", err)
-	}
+	return p.ParseExpression(expressionString)
+}
 
-	p.TokenStream = tokens
-	p.TokenStreamLength = len(tokens)
-	p.TokenStreamPointer = 0
-	p.ConstructedNodes = make([]SpelNode, 0)
+// ParseExpression parses a SpEL expression string into an AST, reporting the
+// tokens and nodes to the configured tracer
+func (p *InternalSpelExpressionParser) ParseExpression(expressionString string) (*SpelExpression, error) {
+	return p.parseExpression(NewSourceText(expressionString), expressionString, 0)
+}
 
-	// Print token stream (matching Java debug output)
-	for count, token := range p.TokenStream {
-		fmt.Printf("[%d] %s\n", count, token)
+// parseExpression parses expressionString, which starts offset characters into
+// source. Positions and spans of the tokens, nodes and errors refer to source,
+// so that the expressions of a template report where they are in the template.
+func (p *InternalSpelExpressionParser) parseExpression(source *SourceText, expressionString string, offset int) (*SpelExpression, error) {
+	if err := p.tokenize(source, expressionString, offset); err != nil {
+		return nil, err
 	}
 
 	// Parse the tokens into an AST
-	ast, err := p.eatExpression()
-	fmt.Println("AST->", ast)
+	ast, err := p.eatTopLevelExpression()
 	if err != nil {
-		return nil, fmt.Errorf("parsing failed: %v", err)
-	}
-
-	if ast == nil {
-		return nil, fmt.Errorf("empty expression - OOD")
-	}
-
-	// Check if there's an assignment operator after the first expression
-	if p.peekToken(ASSIGN) {
-		p.takeToken() // consume '='
-
-		// Parse the right-hand side expression
-		rightExpr, err := p.eatExpression()
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse assignment right-hand side: %v", err)
-		}
-
-		if rightExpr == nil {
-			return nil, fmt.Errorf("expected expression after '='")
-		}
-
-		// Create assignment node
-		assignNode := NewAssign(ast, rightExpr, ast.GetStartPosition(), rightExpr.GetEndPosition())
-		ast = assignNode
+		return nil, err
 	}
 
-	// Print the AST (matching Java debug output)
-	fmt.Println(ast.ToStringAST())
-
 	// Check if all tokens were consumed
 	if p.TokenStreamPointer < p.TokenStreamLength {
-		nextToken := p.peekTokenRaw()
-		return nil, fmt.Errorf("unexpected tokens after expression: %v", nextToken)
+		return nil, p.raiseMoreInput()
+	}
+	if err := p.checkNodeCount(ast); err != nil {
+		return nil, err
 	}
 
-	return NewSpelExpression(expressionString, ast, p.Configuration), nil
+	return p.newSpelExpression(source, expressionString, offset, ast), nil
 }
 
-// ParseExpression parses a SpEL expression string into an AST (without debug output)
-func (p *InternalSpelExpressionParser) ParseExpression(expressionString string) (*SpelExpression, error) {
-	p.ExpressionString = expressionString
+// tokenize prepares the token stream of expressionString, which starts offset
+// characters into source, and reports it to the configured tracer
+func (p *InternalSpelExpressionParser) tokenize(source *SourceText, expressionString string, offset int) error {
+	p.ExpressionString = source.String()
+	p.source = source
+	p.endPos = offset + utf8.RuneCountInString(expressionString)
 
 	// Check expression length
-	if len(expressionString) > p.Configuration.MaximumExpressionLength {
-		return nil, fmt.Errorf("expression exceeds maximum length of %d", p.Configuration.MaximumExpressionLength)
+	if limit := p.Configuration.MaximumExpressionLength; limit > 0 && len(expressionString) > limit {
+		return p.raiseInternalException(offset, MAX_EXPRESSION_LENGTH_EXCEEDED, limit)
 	}
 
 	// Tokenize the expression
-	tokenizer := NewTokenizer(expressionString)
+	tokenizer := NewTokenizerWithConfig(expressionString, p.Configuration)
 	tokens, err := tokenizer.Process()
 	if err != nil {
-		return nil, fmt.Errorf("tokenization failed: %v", err)
+		var parseErr *SpelParseException
+		if offset != 0 && errors.As(err, &parseErr) {
+			return p.raiseInternalException(parseErr.Position+offset, parseErr.Message, parseErr.Inserts...)
+		}
+		return err
+	}
+	if offset != 0 {
+		for _, token := range tokens {
+			token.StartPos += offset
+			token.EndPos += offset
+			token.Span = source.Span(token.StartPos, token.EndPos)
+		}
 	}
 	p.TokenStream = tokens
 	p.TokenStreamLength = len(tokens)
 	p.TokenStreamPointer = 0
 	p.ConstructedNodes = make([]SpelNode, 0)
+	p.nestingDepth = 0
 
-	// Parse the tokens into an AST
+	if tracer := p.Configuration.Tracer; tracer != nil {
+		tracer.TokensProduced(p.ExpressionString, tokens)
+	}
+	return nil
+}
+
+// eatTopLevelExpression parses the whole expression, which may be an
+// assignment. It does not check for tokens left over.
+func (p *InternalSpelExpressionParser) eatTopLevelExpression() (SpelNode, error) {
 	ast, err := p.eatExpression()
 	if err != nil {
-		return nil, fmt.Errorf("parsing failed: %v", err)
+		return nil, err
 	}
 
 	if ast == nil {
-		return nil, fmt.Errorf("empty expression")
+		if p.TokenStreamPointer < p.TokenStreamLength {
+			return nil, p.raiseMoreInput()
+		}
+		return nil, p.raiseInternalException(p.positionOf(nil), OOD)
 	}
 
 	// Check if there's an assignment operator after the first expression
 	if p.peekToken(ASSIGN) {
-		p.takeToken() // consume '='
+		assignToken := p.takeToken() // consume '='
 
 		// Parse the right-hand side expression
 		rightExpr, err := p.eatExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse assignment right-hand side: %v", err)
+			return nil, err
 		}
-
-		if rightExpr == nil {
-			return nil, fmt.Errorf("expected expression after '='")
+		if err := p.checkRightOperand(assignToken, rightExpr); err != nil {
+			return nil, err
 		}
 
 		// Create assignment node
 		assignNode := NewAssign(ast, rightExpr, ast.GetStartPosition(), rightExpr.GetEndPosition())
 		ast = assignNode
 	}
+	return ast, nil
+}
 
-	// Check if all tokens were consumed
-	if p.TokenStreamPointer < p.TokenStreamLength {
-		return nil, fmt.Errorf("unexpected tokens after expression")
+// newSpelExpression completes the parsed tree of expressionString with source
+// spans and reports its nodes to the configured tracer
+func (p *InternalSpelExpressionParser) newSpelExpression(source *SourceText, expressionString string, offset int, ast SpelNode) *SpelExpression {
+	assignSourceSpans(source, ast)
+	if tracer := p.Configuration.Tracer; tracer != nil {
+		traceNodes(tracer, p.ExpressionString, ast)
 	}
-
-	return NewSpelExpression(expressionString, ast, p.Configuration), nil
+	expr := NewSpelExpression(expressionString, ast, p.Configuration)
+	if offset == 0 {
+		expr.source = source
+	}
+	return expr
 }
 
 // eatExpression parses the top-level expression
 func (p *InternalSpelExpressionParser) eatExpression() (SpelNode, error) {
+	if err := p.enterNested(); err != nil {
+		return nil, err
+	}
+	defer p.leaveNested()
 	return p.eatTernaryExpression()
 }
 
@@ -157,33 +183,31 @@ func (p *InternalSpelExpressionParser) eatExpression() (SpelNode, error) {
 func (p *InternalSpelExpressionParser) eatTernaryExpression() (SpelNode, error) {
 	// Check for direct ternary without condition (e.g., "? 1 : 2")
 	if p.peekToken(QMARK) {
-		startPos := p.peekTokenRaw().StartPos
-		p.takeToken() // consume '?'
+		qmarkToken := p.takeToken() // consume '?'
+		startPos := qmarkToken.StartPos
 
 		// Parse true value
 		trueValue, err := p.eatLogicalOrExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse true value in direct ternary expression: %v", err)
+			return nil, err
 		}
-
-		if trueValue == nil {
-			return nil, fmt.Errorf("expected expression after '?' in direct ternary")
+		if err := p.checkRightOperand(qmarkToken, trueValue); err != nil {
+			return nil, err
 		}
 
 		// Expect colon
-		if !p.peekToken(COLON) {
-			return nil, fmt.Errorf("expected ':' in direct ternary expression")
+		colonToken, err := p.eatToken(COLON)
+		if err != nil {
+			return nil, err
 		}
-		p.takeToken() // consume ':'
 
 		// Parse false value
 		falseValue, err := p.eatLogicalOrExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse false value in direct ternary expression: %v", err)
+			return nil, err
 		}
-
-		if falseValue == nil {
-			return nil, fmt.Errorf("expected expression after ':' in direct ternary")
+		if err := p.checkRightOperand(colonToken, falseValue); err != nil {
+			return nil, err
 		}
 
 		// Create ternary node with null condition (or a literal true)
@@ -200,16 +224,18 @@ func (p *InternalSpelExpressionParser) eatTernaryExpression() (SpelNode, error)
 
 	// Check for Elvis operator (?:)
 	if p.peekToken(ELVIS) {
-		p.takeToken() // consume '?:'
+		elvisToken := p.takeToken() // consume '?:'
+		if err := p.checkLeftOperand(elvisToken, expr); err != nil {
+			return nil, err
+		}
 
 		// Parse default value
 		defaultValue, err := p.eatLogicalOrExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse default value in Elvis expression: %v", err)
+			return nil, err
 		}
-
-		if defaultValue == nil {
-			return nil, fmt.Errorf("expected expression after '?:' in Elvis operator")
+		if err := p.checkRightOperand(elvisToken, defaultValue); err != nil {
+			return nil, err
 		}
 
 		// Create Elvis node
@@ -219,32 +245,30 @@ func (p *InternalSpelExpressionParser) eatTernaryExpression() (SpelNode, error)
 
 	// Check for ternary operator
 	if p.peekToken(QMARK) {
-		p.takeToken() // consume '?'
+		qmarkToken := p.takeToken() // consume '?'
 
 		// Parse true value
 		trueValue, err := p.eatLogicalOrExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse true value in ternary expression: %v", err)
+			return nil, err
 		}
-
-		if trueValue == nil {
-			return nil, fmt.Errorf("expected expression after '?' in ternary")
+		if err := p.checkRightOperand(qmarkToken, trueValue); err != nil {
+			return nil, err
 		}
 
 		// Expect colon
-		if !p.peekToken(COLON) {
-			return nil, fmt.Errorf("expected ':' in ternary expression")
+		colonToken, err := p.eatToken(COLON)
+		if err != nil {
+			return nil, err
 		}
-		p.takeToken() // consume ':'
 
 		// Parse false value
 		falseValue, err := p.eatLogicalOrExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse false value in ternary expression: %v", err)
+			return nil, err
 		}
-
-		if falseValue == nil {
-			return nil, fmt.Errorf("expected expression after ':' in ternary")
+		if err := p.checkRightOperand(colonToken, falseValue); err != nil {
+			return nil, err
 		}
 
 		// Create ternary node
@@ -268,8 +292,8 @@ func (p *InternalSpelExpressionParser) eatLogicalOrExpression() (SpelNode, error
 		if err != nil {
 			return nil, err
 		}
-		if right == nil {
-			return nil, fmt.Errorf("missing right operand for OR at position %d", token.StartPos)
+		if err := p.checkOperands(token, expr, right); err != nil {
+			return nil, err
 		}
 		expr = NewOpOr(expr, right, expr.GetStartPosition(), right.GetEndPosition())
 	}
@@ -290,8 +314,8 @@ func (p *InternalSpelExpressionParser) eatLogicalAndExpression() (SpelNode, erro
 		if err != nil {
 			return nil, err
 		}
-		if right == nil {
-			return nil, fmt.Errorf("missing right operand for AND at position %d", token.StartPos)
+		if err := p.checkOperands(token, expr, right); err != nil {
+			return nil, err
 		}
 		expr = NewOpAnd(expr, right, expr.GetStartPosition(), right.GetEndPosition())
 	}
@@ -312,9 +336,8 @@ func (p *InternalSpelExpressionParser) eatRelationalExpression() (SpelNode, erro
 		if err != nil {
 			return nil, err
 		}
-		if right == nil {
-			return nil, fmt.Errorf("missing right operand for %s at position %d",
-				relationalOperatorToken.Kind.String(), relationalOperatorToken.StartPos)
+		if err := p.checkOperands(relationalOperatorToken, expr, right); err != nil {
+			return nil, err
 		}
 
 		startPos := expr.GetStartPosition()
@@ -337,8 +360,11 @@ func (p *InternalSpelExpressionParser) eatRelationalExpression() (SpelNode, erro
 			expr = NewOperatorMatches(expr, right, startPos, endPos)
 		case BETWEEN:
 			expr = NewOperatorBetween(expr, right, startPos, endPos)
+		case INSTANCEOF:
+			expr = NewOperatorInstanceof(expr, right, startPos, endPos)
 		default:
-			return nil, fmt.Errorf("unsupported relational operator: %s", relationalOperatorToken.Kind.String())
+			return nil, p.raiseInternalException(relationalOperatorToken.StartPos, NOT_EXPECTED_TOKEN,
+				"relational operator", p.tokenText(relationalOperatorToken))
 		}
 	}
 
@@ -358,9 +384,8 @@ func (p *InternalSpelExpressionParser) eatSumExpression() (SpelNode, error) {
 		if err != nil {
 			return nil, err
 		}
-		if right == nil {
-			return nil, fmt.Errorf("missing right operand for %s at position %d",
-				token.Kind.String(), token.StartPos)
+		if err := p.checkOperands(token, expr, right); err != nil {
+			return nil, err
 		}
 
 		startPos := expr.GetStartPosition()
@@ -390,9 +415,8 @@ func (p *InternalSpelExpressionParser) eatProductExpression() (SpelNode, error)
 		if err != nil {
 			return nil, err
 		}
-		if right == nil {
-			return nil, fmt.Errorf("missing right operand for %s at position %d",
-				token.Kind.String(), token.StartPos)
+		if err := p.checkOperands(token, expr, right); err != nil {
+			return nil, err
 		}
 
 		startPos := expr.GetStartPosition()
@@ -402,9 +426,13 @@ func (p *InternalSpelExpressionParser) eatProductExpression() (SpelNode, error)
 		case STAR:
 			expr = NewOpMultiply(expr, right, startPos, endPos)
 		case DIV:
-			expr = NewOpDivide(expr, right, startPos, endPos)
+			divide := NewOpDivide(expr, right, startPos, endPos)
+			divide.OperatorPos = token.StartPos
+			expr = divide
 		case MOD:
-			expr = NewOpModulus(expr, right, startPos, endPos)
+			modulus := NewOpModulus(expr, right, startPos, endPos)
+			modulus.OperatorPos = token.StartPos
+			expr = modulus
 		}
 	}
 
@@ -424,8 +452,8 @@ func (p *InternalSpelExpressionParser) eatPowerIncDecExpression() (SpelNode, err
 		if err != nil {
 			return nil, err
 		}
-		if right == nil {
-			return nil, fmt.Errorf("missing right operand for power operator at position %d", token.StartPos)
+		if err := p.checkOperands(token, expr, right); err != nil {
+			return nil, err
 		}
 
 		startPos := expr.GetStartPosition()
@@ -433,7 +461,14 @@ func (p *InternalSpelExpressionParser) eatPowerIncDecExpression() (SpelNode, err
 		return NewOperatorPower(expr, right, startPos, endPos), nil
 	}
 
-	// TODO: Handle INC/DEC operators if needed
+	if expr != nil && (p.peekToken(INC) || p.peekToken(DEC)) {
+		token := p.takeToken() // consume postfix ++ or --
+		if token.Kind == INC {
+			return NewPostfixOpInc(expr, expr.GetStartPosition(), token.EndPos), nil
+		}
+		return NewPostfixOpDec(expr, expr.GetStartPosition(), token.EndPos), nil
+	}
+
 	return expr, nil
 }
 
@@ -441,13 +476,16 @@ func (p *InternalSpelExpressionParser) eatPowerIncDecExpression() (SpelNode, err
 func (p *InternalSpelExpressionParser) eatUnaryExpression() (SpelNode, error) {
 	if p.peekToken(NOT) || p.peekToken(PLUS) || p.peekToken(MINUS) || p.peekToken(INC) || p.peekToken(DEC) {
 		token := p.takeToken()
+		if err := p.enterNested(); err != nil {
+			return nil, err
+		}
 		child, err := p.eatUnaryExpression()
+		p.leaveNested()
 		if err != nil {
 			return nil, err
 		}
-		if child == nil {
-			return nil, fmt.Errorf("missing operand for unary %s at position %d",
-				token.Kind.String(), token.StartPos)
+		if err := p.checkRightOperand(token, child); err != nil {
+			return nil, err
 		}
 
 		switch token.Kind {
@@ -478,7 +516,7 @@ func (p *InternalSpelExpressionParser) eatPrimaryExpression() (SpelNode, error)
 		return nil, err
 	}
 	if start == nil {
-		return nil, fmt.Errorf("expected primary expression")
+		return nil, nil
 	}
 
 	// Handle compound expressions (property access, method calls, etc.)
@@ -506,53 +544,35 @@ func (p *InternalSpelExpressionParser) eatPrimaryExpression() (SpelNode, error)
 		nodes...), nil
 }
 
-// eatStartNode parses start nodes (literals, identifiers, parenthesized expressions)
+// eatStartNode parses start nodes (literals, identifiers, parenthesized expressions).
+// It returns nil if no start node begins at the current token.
 func (p *InternalSpelExpressionParser) eatStartNode() (SpelNode, error) {
-	if p.maybeEatLiteral() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatParenExpression() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatBeanReference() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatVariableReference() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatNullReference() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatTypeReference() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatConstructorExpression() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatInlineCollection() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatInlineList() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatMethodCall() {
-		return p.pop(), nil
-	}
-
-	if p.maybeEatIdentifier() {
-		return p.pop(), nil
+	maybeEats := []func() bool{
+		p.maybeEatLiteral,
+		p.maybeEatParenExpression,
+		p.maybeEatBeanReference,
+		p.maybeEatVariableReference,
+		p.maybeEatNullReference,
+		p.maybeEatTypeReference,
+		p.maybeEatConstructorExpression,
+		p.maybeEatInlineCollection,
+		p.maybeEatInlineList,
+		p.maybeEatMethodCall,
+		p.maybeEatIdentifier,
+	}
+
+	for _, maybeEat := range maybeEats {
+		if maybeEat() {
+			return p.pop(), nil
+		}
+		// A maybeEat method that failed after consuming tokens records why
+		if err := p.pendingError; err != nil {
+			p.pendingError = nil
+			return nil, err
+		}
 	}
 
-	return nil, fmt.Errorf("unexpected token: %v", p.peekTokenRaw())
+	return nil, nil
 }
 
 // eatNode parses node expressions (property access, indexing, method calls, etc.)
@@ -568,16 +588,20 @@ func (p *InternalSpelExpressionParser) eatNode() (SpelNode, error) {
 		// Check for nested indexing like [[expr]]
 		if p.peekToken(LSQUARE) {
 			// This is nested indexing, parse recursively
+			if err := p.enterNested(); err != nil {
+				return nil, err
+			}
 			nestedIndexer, err := p.eatNode() // This will parse the nested [expr]
+			p.leaveNested()
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse nested index expression: %v", err)
+				return nil, err
 			}
 
 			// Expect closing bracket
-			if !p.peekToken(RSQUARE) {
-				return nil, fmt.Errorf("expected ']' after nested index expression")
+			endToken, err := p.eatToken(RSQUARE)
+			if err != nil {
+				return nil, err
 			}
-			endToken := p.takeToken() // consume ']'
 
 			// Create indexer node with nested indexer
 			indexer := NewIndexer(nestedIndexer, startToken.StartPos, endToken.EndPos)
@@ -585,16 +609,16 @@ func (p *InternalSpelExpressionParser) eatNode() (SpelNode, error) {
 		}
 
 		// Parse the index expression normally
-		indexExpr, err := p.eatExpression()
+		indexExpr, err := p.eatRequiredExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse index expression: %v", err)
+			return nil, err
 		}
 
 		// Expect closing bracket
-		if !p.peekToken(RSQUARE) {
-			return nil, fmt.Errorf("expected ']' after index expression")
+		endToken, err := p.eatToken(RSQUARE)
+		if err != nil {
+			return nil, err
 		}
-		endToken := p.takeToken() // consume ']'
 
 		// Create indexer node
 		indexer := NewIndexer(indexExpr, startToken.StartPos, endToken.EndPos)
@@ -616,52 +640,50 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 
 	// Check for selection expressions like .?[criteria] or .![criteria]
 	if p.peekToken(SELECT) {
-		p.takeToken() // consume '?['
+		selectToken := p.takeToken() // consume '?['
 
 		// Parse the selection criteria
 		criteria, err := p.eatExpression()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing selection criteria: %v", err)
+			return nil, err
 		}
 
 		if criteria == nil {
-			return nil, fmt.Errorf("expected criteria in selection expression")
+			return nil, p.raiseInternalException(selectToken.StartPos, MISSING_SELECTION_EXPRESSION)
 		}
 
 		// Expect closing bracket
-		if !p.peekToken(RSQUARE) {
-			return nil, fmt.Errorf("expected ']' to close selection expression")
+		endToken, err := p.eatToken(RSQUARE)
+		if err != nil {
+			return nil, err
 		}
-
-		endToken := p.takeToken() // consume ']'
 		endPos := endToken.EndPos
 
-		// Create selection node (.?[ is always null-safe selection)
-		selection := NewSelection(true, SelectionAll, criteria, token.StartPos, endPos)
+		// Create selection node (null-safe if preceded by ?.)
+		selection := NewSelection(nullSafeNavigation, SelectionAll, criteria, token.StartPos, endPos)
 		p.push(selection)
 		return selection, nil
 	}
 
 	// Check for first selection expressions like .^[criteria] or ?^[criteria]
 	if p.peekToken(SELECT_FIRST) {
-		p.takeToken() // consume '^['
+		selectToken := p.takeToken() // consume '^['
 
 		// Parse the selection criteria
 		criteria, err := p.eatExpression()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing first selection criteria: %v", err)
+			return nil, err
 		}
 
 		if criteria == nil {
-			return nil, fmt.Errorf("expected criteria in first selection expression")
+			return nil, p.raiseInternalException(selectToken.StartPos, MISSING_SELECTION_EXPRESSION)
 		}
 
 		// Expect closing bracket
-		if !p.peekToken(RSQUARE) {
-			return nil, fmt.Errorf("expected ']' to close first selection expression")
+		endToken, err := p.eatToken(RSQUARE)
+		if err != nil {
+			return nil, err
 		}
-
-		endToken := p.takeToken() // consume ']'
 		endPos := endToken.EndPos
 
 		// Create first selection node
@@ -672,24 +694,23 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 
 	// Check for last selection expressions like .$[criteria] or ?$[criteria]
 	if p.peekToken(SELECT_LAST) {
-		p.takeToken() // consume '$['
+		selectToken := p.takeToken() // consume '$['
 
 		// Parse the selection criteria
 		criteria, err := p.eatExpression()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing last selection criteria: %v", err)
+			return nil, err
 		}
 
 		if criteria == nil {
-			return nil, fmt.Errorf("expected criteria in last selection expression")
+			return nil, p.raiseInternalException(selectToken.StartPos, MISSING_SELECTION_EXPRESSION)
 		}
 
 		// Expect closing bracket
-		if !p.peekToken(RSQUARE) {
-			return nil, fmt.Errorf("expected ']' to close last selection expression")
+		endToken, err := p.eatToken(RSQUARE)
+		if err != nil {
+			return nil, err
 		}
-
-		endToken := p.takeToken() // consume ']'
 		endPos := endToken.EndPos
 
 		// Create last selection node
@@ -703,25 +724,25 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 		p.takeToken() // consume '!['
 
 		// Parse the projection expression
-		projectionExpr, err := p.eatExpression()
+		projectionExpr, err := p.eatRequiredExpression()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing projection expression: %v", err)
-		}
-
-		if projectionExpr == nil {
-			return nil, fmt.Errorf("expected expression in projection")
+			return nil, err
 		}
 
 		// Expect closing bracket
-		if !p.peekToken(RSQUARE) {
-			return nil, fmt.Errorf("expected ']' to close projection expression")
+		endToken, err := p.eatToken(RSQUARE)
+		if err != nil {
+			return nil, err
 		}
-
-		endToken := p.takeToken() // consume ']'
 		endPos := endToken.EndPos
 
-		// Create projection node
-		projection := NewProjection(projectionExpr, token.StartPos, endPos)
+		// Create projection node (null-safe if preceded by ?.)
+		var projection *Projection
+		if nullSafeNavigation {
+			projection = NewNullSafeProjection(projectionExpr, token.StartPos, endPos)
+		} else {
+			projection = NewProjection(projectionExpr, token.StartPos, endPos)
+		}
 		p.push(projection)
 		return projection, nil
 	}
@@ -731,20 +752,16 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 		p.takeToken() // consume '['
 
 		// Parse the index expression
-		indexExpr, err := p.eatExpression()
+		indexExpr, err := p.eatRequiredExpression()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse index expression: %v", err)
-		}
-
-		if indexExpr == nil {
-			return nil, fmt.Errorf("expected index expression")
+			return nil, err
 		}
 
 		// Expect closing bracket
-		if !p.peekToken(RSQUARE) {
-			return nil, fmt.Errorf("expected ']' after index expression")
+		endBracket, err := p.eatToken(RSQUARE)
+		if err != nil {
+			return nil, err
 		}
-		endBracket := p.takeToken() // consume ']'
 
 		// Create indexer node (null-safe if preceded by ?.)
 		var indexer *Indexer
@@ -780,12 +797,9 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 
 			// Parse arguments separated by commas
 			for {
-				arg, err := p.eatExpression()
+				arg, err := p.eatArgument()
 				if err != nil {
-					return nil, fmt.Errorf("error parsing method argument: %v", err)
-				}
-				if arg == nil {
-					return nil, fmt.Errorf("expected argument in method call")
+					return nil, err
 				}
 				arguments = append(arguments, arg)
 
@@ -800,11 +814,10 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 				break
 			}
 
-			if !p.peekToken(RPAREN) {
-				return nil, fmt.Errorf("expected ')' to close method call")
+			endToken, err := p.eatToken(RPAREN)
+			if err != nil {
+				return nil, err
 			}
-
-			endToken := p.takeToken() // consume ')'
 			endPos := endToken.EndPos
 
 			methodRef := NewMethodReference(nullSafeNavigation, propertyName, arguments, token.StartPos, endPos)
@@ -818,7 +831,10 @@ func (p *InternalSpelExpressionParser) eatDottedNode() (SpelNode, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("expected identifier after %s at position %d", token.Kind.String(), token.StartPos)
+	if nextToken := p.peekTokenRaw(); nextToken != nil {
+		return nil, p.raiseInternalException(token.StartPos, UNEXPECTED_DATA_AFTER_DOT, p.tokenText(nextToken))
+	}
+	return nil, p.raiseInternalException(token.StartPos, OOD)
 }
 
 // Literal parsing methods
@@ -833,7 +849,7 @@ func (p *InternalSpelExpressionParser) maybeEatLiteral() bool {
 		p.takeToken()
 		value, err := parseNumber(token.StringValue(), token.Kind)
 		if err != nil {
-			return false
+			return p.recordError(p.raiseNumberException(token))
 		}
 		literal := NewIntLiteral(value, token.StartPos, token.EndPos)
 		p.push(literal)
@@ -843,14 +859,14 @@ func (p *InternalSpelExpressionParser) maybeEatLiteral() bool {
 		p.takeToken()
 		value, err := parseNumber(token.StringValue(), token.Kind)
 		if err != nil {
-			return false
+			return p.recordError(p.raiseNumberException(token))
 		}
 		// Create RealLiteral for floating point numbers
 		if floatVal, ok := value.(float64); ok {
 			realLiteral := NewRealLiteral(floatVal, token.StartPos, token.EndPos)
 			p.push(realLiteral)
 		} else if floatVal, ok := value.(float32); ok {
-			realLiteral := NewRealLiteral(float64(floatVal), token.StartPos, token.EndPos)
+			realLiteral := NewFloatLiteral(floatVal, token.StartPos, token.EndPos)
 			p.push(realLiteral)
 		} else {
 			// Fallback to regular literal
@@ -891,16 +907,14 @@ func (p *InternalSpelExpressionParser) maybeEatParenExpression() bool {
 	}
 
 	p.takeToken() // consume '('
-	expr, err := p.eatExpression()
+	expr, err := p.eatRequiredExpression()
 	if err != nil {
-		return false
+		return p.recordError(err)
 	}
 
-	if !p.peekToken(RPAREN) {
-		return false
+	if _, err := p.eatToken(RPAREN); err != nil {
+		return p.recordError(err)
 	}
-
-	p.takeToken() // consume ')'
 	p.push(expr)
 	return true
 }
@@ -913,7 +927,7 @@ func (p *InternalSpelExpressionParser) maybeEatBeanReference() bool {
 	token := p.takeToken()
 
 	if !p.peekToken(IDENTIFIER) && !p.peekToken(LITERAL_STRING) {
-		return false
+		return p.recordError(p.raiseInternalException(token.StartPos, INVALID_BEAN_REFERENCE))
 	}
 
 	nameToken := p.takeToken()
@@ -924,6 +938,10 @@ func (p *InternalSpelExpressionParser) maybeEatBeanReference() bool {
 		beanName = beanName[1 : len(beanName)-1]
 	}
 
+	if token.Kind == FACTORY_BEAN_REF {
+		p.push(NewFactoryBeanReference(beanName, token.StartPos, nameToken.EndPos))
+		return true
+	}
 	beanRef := NewBeanReference(beanName, token.StartPos, nameToken.EndPos)
 	p.push(beanRef)
 	return true
@@ -936,11 +954,10 @@ func (p *InternalSpelExpressionParser) maybeEatVariableReference() bool {
 
 	hashToken := p.takeToken()
 
-	if !p.peekToken(IDENTIFIER) {
-		return false
+	identToken, err := p.eatToken(IDENTIFIER)
+	if err != nil {
+		return p.recordError(err)
 	}
-
-	identToken := p.takeToken()
 	varName := identToken.StringValue()
 
 	// Check if this is a function call (#identifier(...))
@@ -961,12 +978,9 @@ func (p *InternalSpelExpressionParser) maybeEatVariableReference() bool {
 
 		// Parse arguments separated by commas
 		for {
-			arg, err := p.eatExpression()
+			arg, err := p.eatArgument()
 			if err != nil {
-				return false
-			}
-			if arg == nil {
-				return false
+				return p.recordError(err)
 			}
 			arguments = append(arguments, arg)
 
@@ -977,11 +991,10 @@ func (p *InternalSpelExpressionParser) maybeEatVariableReference() bool {
 			break
 		}
 
-		if !p.peekToken(RPAREN) {
-			return false
+		endToken, err := p.eatToken(RPAREN)
+		if err != nil {
+			return p.recordError(err)
 		}
-
-		endToken := p.takeToken() // consume ')'
 		endPos := endToken.EndPos
 
 		funcRef := NewFunctionReference(varName, arguments, hashToken.StartPos, endPos)
@@ -1048,9 +1061,9 @@ func (p *InternalSpelExpressionParser) maybeEatInlineCollection() bool {
 	}
 
 	// Parse first element to determine if it's a list or map
-	firstExpr, err := p.eatExpression()
+	firstExpr, err := p.eatRequiredExpression()
 	if err != nil {
-		return false
+		return p.recordError(err)
 	}
 
 	// Check if this is a map (key:value format)
@@ -1059,9 +1072,9 @@ func (p *InternalSpelExpressionParser) maybeEatInlineCollection() bool {
 		p.takeToken() // consume ':'
 
 		// Parse first value
-		firstValue, err := p.eatExpression()
+		firstValue, err := p.eatRequiredExpression()
 		if err != nil {
-			return false
+			return p.recordError(err)
 		}
 
 		pairs := []KeyValuePair{
@@ -1073,31 +1086,30 @@ func (p *InternalSpelExpressionParser) maybeEatInlineCollection() bool {
 			p.takeToken() // consume ','
 
 			// Parse key
-			key, err := p.eatExpression()
+			key, err := p.eatRequiredExpression()
 			if err != nil {
-				return false
+				return p.recordError(err)
 			}
 
 			// Expect colon
-			if !p.peekToken(COLON) {
-				return false
+			if _, err := p.eatToken(COLON); err != nil {
+				return p.recordError(err)
 			}
-			p.takeToken() // consume ':'
 
 			// Parse value
-			value, err := p.eatExpression()
+			value, err := p.eatRequiredExpression()
 			if err != nil {
-				return false
+				return p.recordError(err)
 			}
 
 			pairs = append(pairs, KeyValuePair{Key: key, Value: value})
 		}
 
 		// Expect closing brace
-		if !p.peekToken(RCURLY) {
-			return false
+		endToken, err := p.eatToken(RCURLY)
+		if err != nil {
+			return p.recordError(err)
 		}
-		endToken := p.takeToken() // consume '}'
 
 		inlineMap := NewInlineMap(pairs, startToken.StartPos, endToken.EndPos)
 		p.push(inlineMap)
@@ -1109,18 +1121,18 @@ func (p *InternalSpelExpressionParser) maybeEatInlineCollection() bool {
 		// Parse remaining elements
 		for p.peekToken(COMMA) {
 			p.takeToken() // consume ','
-			expr, err := p.eatExpression()
+			expr, err := p.eatRequiredExpression()
 			if err != nil {
-				return false
+				return p.recordError(err)
 			}
 			elements = append(elements, expr)
 		}
 
 		// Expect closing brace
-		if !p.peekToken(RCURLY) {
-			return false
+		endToken, err := p.eatToken(RCURLY)
+		if err != nil {
+			return p.recordError(err)
 		}
-		endToken := p.takeToken() // consume '}'
 
 		inlineList := NewInlineList(elements, startToken.StartPos, endToken.EndPos)
 		p.push(inlineList)
@@ -1149,9 +1161,9 @@ func (p *InternalSpelExpressionParser) maybeEatInlineList() bool {
 	var elements []SpelNode
 
 	// Parse first element
-	firstExpr, err := p.eatExpression()
+	firstExpr, err := p.eatRequiredExpression()
 	if err != nil {
-		return false
+		return p.recordError(err)
 	}
 	elements = append(elements, firstExpr)
 
@@ -1164,18 +1176,18 @@ func (p *InternalSpelExpressionParser) maybeEatInlineList() bool {
 			break // Allow trailing comma
 		}
 
-		element, err := p.eatExpression()
+		element, err := p.eatRequiredExpression()
 		if err != nil {
-			return false
+			return p.recordError(err)
 		}
 		elements = append(elements, element)
 	}
 
 	// Expect closing bracket
-	if !p.peekToken(RSQUARE) {
-		return false
+	endToken, err := p.eatToken(RSQUARE)
+	if err != nil {
+		return p.recordError(err)
 	}
-	endToken := p.takeToken() // consume ']'
 
 	// Create inline list for array literal
 	inlineList := NewInlineList(elements, startToken.StartPos, endToken.EndPos)
@@ -1235,12 +1247,9 @@ func (p *InternalSpelExpressionParser) maybeEatMethodCall() bool {
 
 	// Parse arguments separated by commas
 	for {
-		arg, err := p.eatExpression()
+		arg, err := p.eatArgument()
 		if err != nil {
-			return false
-		}
-		if arg == nil {
-			return false
+			return p.recordError(err)
 		}
 		arguments = append(arguments, arg)
 
@@ -1251,11 +1260,10 @@ func (p *InternalSpelExpressionParser) maybeEatMethodCall() bool {
 		break
 	}
 
-	if !p.peekToken(RPAREN) {
-		return false
+	endToken, err := p.eatToken(RPAREN)
+	if err != nil {
+		return p.recordError(err)
 	}
-
-	endToken := p.takeToken() // consume ')'
 	endPos := endToken.EndPos
 
 	methodRef := NewMethodReference(false, methodName, arguments, startPos, endPos)
@@ -1318,6 +1326,176 @@ func (p *InternalSpelExpressionParser) maybeEatRelationalOperator() *Token {
 	return nil
 }
 
+// raiseInternalException returns a SpelParseException for the expression being parsed
+func (p *InternalSpelExpressionParser) raiseInternalException(position int, message SpelMessage, inserts ...interface{}) error {
+	return newSpelParseException(p.source, position, message, inserts...)
+}
+
+// raiseNotExpectedToken reports that the next token is not of the expected kind
+func (p *InternalSpelExpressionParser) raiseNotExpectedToken(expectedKind TokenKind) error {
+	token := p.peekTokenRaw()
+	if token == nil {
+		return p.raiseInternalException(p.positionOf(nil), OOD)
+	}
+	expected := expectedKind.TokenChars()
+	if expected == "" {
+		expected = strings.ToLower(expectedKind.String())
+	}
+	return p.raiseInternalException(token.StartPos, NOT_EXPECTED_TOKEN, expected, p.tokenText(token))
+}
+
+// raiseMoreInput reports the tokens left over after a complete expression
+func (p *InternalSpelExpressionParser) raiseMoreInput() error {
+	token := p.peekTokenRaw()
+	return p.raiseInternalException(token.StartPos, MORE_INPUT, p.source.Text(token.StartPos, p.endPos))
+}
+
+// raiseNumberException reports a numeric literal that does not fit its type
+func (p *InternalSpelExpressionParser) raiseNumberException(token *Token) error {
+	switch token.Kind {
+	case LITERAL_LONG, LITERAL_HEXLONG:
+		return p.raiseInternalException(token.StartPos, NOT_A_LONG, p.tokenText(token))
+	case LITERAL_REAL, LITERAL_REAL_FLOAT:
+		return p.raiseInternalException(token.StartPos, NOT_A_REAL, p.tokenText(token))
+	}
+	return p.raiseInternalException(token.StartPos, NOT_AN_INTEGER, p.tokenText(token))
+}
+
+// recordError keeps the first error of a maybeEat method, which can only report
+// failure, for eatStartNode to return. It always returns false.
+func (p *InternalSpelExpressionParser) recordError(err error) bool {
+	if p.pendingError == nil {
+		p.pendingError = err
+	}
+	return false
+}
+
+// positionOf returns the start of token, or the end of the expression for nil
+func (p *InternalSpelExpressionParser) positionOf(token *Token) int {
+	if token == nil {
+		return p.endPos
+	}
+	return token.StartPos
+}
+
+// tokenText returns the source text of token
+func (p *InternalSpelExpressionParser) tokenText(token *Token) string {
+	if token.StartPos < 0 || token.StartPos > token.EndPos || token.EndPos > p.source.length {
+		return token.Kind.TokenChars()
+	}
+	return p.source.Text(token.StartPos, token.EndPos)
+}
+
+// eatToken consumes the next token, which must be of the expected kind. While
+// recovering, a missing closing bracket is looked for after unexpected tokens.
+func (p *InternalSpelExpressionParser) eatToken(expectedKind TokenKind) (*Token, error) {
+	if !p.peekToken(expectedKind) {
+		err := p.raiseNotExpectedToken(expectedKind)
+		if p.recovering && isClosingKind(expectedKind) {
+			p.reportError(err)
+			p.skipTokens(false)
+			if p.peekToken(expectedKind) {
+				return p.takeToken(), nil
+			}
+		}
+		return nil, err
+	}
+	return p.takeToken(), nil
+}
+
+// eatRequiredExpression parses an expression that must be present, such as an
+// index or a list element
+func (p *InternalSpelExpressionParser) eatRequiredExpression() (SpelNode, error) {
+	return p.eatRecoverable(func() (SpelNode, error) {
+		expr, err := p.eatExpression()
+		if err != nil {
+			return nil, err
+		}
+		if expr == nil {
+			token := p.peekTokenRaw()
+			if token == nil {
+				return nil, p.raiseInternalException(p.positionOf(nil), OOD)
+			}
+			return nil, p.raiseInternalException(token.StartPos, NOT_EXPECTED_TOKEN, "expression", p.tokenText(token))
+		}
+		return expr, nil
+	})
+}
+
+// eatArgument parses a method, function or constructor argument
+func (p *InternalSpelExpressionParser) eatArgument() (SpelNode, error) {
+	return p.eatRecoverable(func() (SpelNode, error) {
+		if p.peekTokenRaw() == nil {
+			return nil, p.raiseInternalException(p.positionOf(nil), RUN_OUT_OF_ARGUMENTS)
+		}
+		return p.eatRequiredExpression()
+	})
+}
+
+// checkOperands fails if either operand of a binary operator is missing
+func (p *InternalSpelExpressionParser) checkOperands(operatorToken *Token, left, right SpelNode) error {
+	if err := p.checkLeftOperand(operatorToken, left); err != nil {
+		return err
+	}
+	return p.checkRightOperand(operatorToken, right)
+}
+
+func (p *InternalSpelExpressionParser) checkLeftOperand(operatorToken *Token, operand SpelNode) error {
+	if operand == nil {
+		return p.raiseInternalException(operatorToken.StartPos, LEFT_OPERAND_PROBLEM, p.tokenText(operatorToken))
+	}
+	return nil
+}
+
+func (p *InternalSpelExpressionParser) checkRightOperand(operatorToken *Token, operand SpelNode) error {
+	if operand == nil {
+		return p.raiseInternalException(operatorToken.StartPos, RIGHT_OPERAND_PROBLEM, p.tokenText(operatorToken))
+	}
+	return nil
+}
+
+// enterNested counts one more level of nesting, failing at the next token once
+// MaxNestingDepth is exceeded. Each call must be followed by leaveNested.
+func (p *InternalSpelExpressionParser) enterNested() error {
+	p.nestingDepth++
+	if limit := p.Configuration.MaxNestingDepth; limit > 0 && p.nestingDepth > limit {
+		p.nestingDepth--
+		return p.raiseInternalException(p.positionOf(p.peekTokenRaw()), MAX_NESTING_DEPTH_EXCEEDED, limit)
+	}
+	return nil
+}
+
+func (p *InternalSpelExpressionParser) leaveNested() {
+	p.nestingDepth--
+}
+
+// checkNodeCount fails at the first node beyond MaxNodeCount in the tree of
+// ast. The tree is walked without recursion since chains of operators make it
+// deep.
+func (p *InternalSpelExpressionParser) checkNodeCount(ast SpelNode) error {
+	limit := p.Configuration.MaxNodeCount
+	if limit <= 0 {
+		return nil
+	}
+	count := 0
+	pending := []SpelNode{ast}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if node == nil {
+			continue
+		}
+		if count++; count > limit {
+			return p.raiseInternalException(node.GetStartPosition(), MAX_NODE_COUNT_EXCEEDED, limit)
+		}
+		children := node.GetChildren()
+		for i := len(children) - 1; i >= 0; i-- {
+			pending = append(pending, children[i])
+		}
+	}
+	return nil
+}
+
 // Node stack operations
 func (p *InternalSpelExpressionParser) push(node SpelNode) {
 	p.ConstructedNodes = append(p.ConstructedNodes, node)
@@ -1335,12 +1513,11 @@ func (p *InternalSpelExpressionParser) pop() SpelNode {
 
 // eatMethodCall parses method calls with arguments
 func (p *InternalSpelExpressionParser) eatMethodCall() (SpelNode, error) {
-	if !p.peekToken(LPAREN) {
-		return nil, fmt.Errorf("expected '(' for method call")
+	startToken, err := p.eatToken(LPAREN)
+	if err != nil {
+		return nil, err
 	}
-
-	startPos := p.peekTokenRaw().StartPos
-	p.takeToken() // consume '('
+	startPos := startToken.StartPos
 
 	// Parse arguments
 	var arguments []SpelNode
@@ -1356,12 +1533,9 @@ func (p *InternalSpelExpressionParser) eatMethodCall() (SpelNode, error) {
 
 	// Parse arguments separated by commas
 	for {
-		arg, err := p.eatExpression()
+		arg, err := p.eatArgument()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing method argument: %v", err)
-		}
-		if arg == nil {
-			return nil, fmt.Errorf("expected argument in method call")
+			return nil, err
 		}
 		arguments = append(arguments, arg)
 
@@ -1372,11 +1546,10 @@ func (p *InternalSpelExpressionParser) eatMethodCall() (SpelNode, error) {
 		break
 	}
 
-	if !p.peekToken(RPAREN) {
-		return nil, fmt.Errorf("expected ')' to close method call")
+	endToken, err := p.eatToken(RPAREN)
+	if err != nil {
+		return nil, err
 	}
-
-	endToken := p.takeToken() // consume ')'
 	endPos := endToken.EndPos
 
 	methodCall := NewMethodReference(false, "", arguments, startPos, endPos)
@@ -1400,9 +1573,7 @@ func (p *InternalSpelExpressionParser) maybeEatConstructorExpression() bool {
 	if !p.peekToken(IDENTIFIER) && !p.peekToken(LITERAL_INT) && !p.peekToken(LITERAL_LONG) &&
 		!p.peekToken(LITERAL_HEXINT) && !p.peekToken(LITERAL_HEXLONG) {
 		// 'new' keyword found but no valid type name follows
-		// Put the token back and let the normal error handling in eatStartNode handle it
-		p.TokenStreamPointer--
-		return false
+		return p.recordError(p.raiseNotExpectedToken(IDENTIFIER))
 	}
 
 	// Collect type name parts - accept identifiers and numeric literals
@@ -1447,9 +1618,7 @@ func (p *InternalSpelExpressionParser) maybeEatConstructorExpression() bool {
 
 	// Regular constructor call with parentheses
 	if !p.peekToken(LPAREN) {
-		// Not a constructor call, put back tokens
-		p.TokenStreamPointer = newToken.StartPos
-		return false
+		return p.recordError(p.raiseInternalException(p.positionOf(p.peekTokenRaw()), MISSING_CONSTRUCTOR_ARGS))
 	}
 
 	p.takeToken() // consume '('
@@ -1468,12 +1637,9 @@ func (p *InternalSpelExpressionParser) maybeEatConstructorExpression() bool {
 
 	// Parse arguments separated by commas
 	for {
-		arg, err := p.eatExpression()
+		arg, err := p.eatArgument()
 		if err != nil {
-			return false
-		}
-		if arg == nil {
-			return false
+			return p.recordError(err)
 		}
 		arguments = append(arguments, arg)
 
@@ -1484,11 +1650,10 @@ func (p *InternalSpelExpressionParser) maybeEatConstructorExpression() bool {
 		break
 	}
 
-	if !p.peekToken(RPAREN) {
-		return false
+	endToken, err := p.eatToken(RPAREN)
+	if err != nil {
+		return p.recordError(err)
 	}
-
-	endToken := p.takeToken() // consume ')'
 	endPos := endToken.EndPos
 
 	constructor := NewConstructorReference(typeName, qualifierNode, arguments, newToken.StartPos, endPos)
@@ -1508,23 +1673,23 @@ func (p *InternalSpelExpressionParser) maybeEatArrayConstructor(newToken *Token,
 	if p.tryParseArrayConstructorWithSizes(newToken, typeName) {
 		return true
 	}
+	if p.pendingError != nil {
+		return false
+	}
 
 	// Count and consume array dimensions [][]...
 	dimensionCount := 0
 	for p.peekToken(LSQUARE) {
 		p.takeToken() // consume '['
 
-		if !p.peekToken(RSQUARE) {
-			// Not an empty bracket pair, not an array constructor
-			return false
+		if _, err := p.eatToken(RSQUARE); err != nil {
+			return p.recordError(err)
 		}
-
-		p.takeToken() // consume ']'
 		dimensionCount++
 	}
 
 	if dimensionCount == 0 || !p.peekToken(LCURLY) {
-		return false
+		return p.recordError(p.raiseInternalException(newToken.StartPos, MISSING_ARRAY_DIMENSION))
 	}
 
 	// Build array type name with dimensions (e.g., "int" -> "int[][]")
@@ -1548,12 +1713,9 @@ func (p *InternalSpelExpressionParser) maybeEatArrayConstructor(newToken *Token,
 	} else {
 		// Parse elements separated by commas
 		for {
-			element, err := p.eatExpression()
+			element, err := p.eatRequiredExpression()
 			if err != nil {
-				return false
-			}
-			if element == nil {
-				return false
+				return p.recordError(err)
 			}
 			elements = append(elements, element)
 
@@ -1564,10 +1726,9 @@ func (p *InternalSpelExpressionParser) maybeEatArrayConstructor(newToken *Token,
 			break
 		}
 
-		if !p.peekToken(RCURLY) {
-			return false
+		if _, err := p.eatToken(RCURLY); err != nil {
+			return p.recordError(err)
 		}
-		p.takeToken() // consume '}'
 	}
 
 	endToken := p.TokenStream[p.TokenStreamPointer-1]
@@ -1591,6 +1752,7 @@ func (p *InternalSpelExpressionParser) maybeEatArrayConstructor(newToken *Token,
 	// Create ConstructorReference with array type and InlineList as arguments
 	arguments := []SpelNode{inlineList}
 	constructorRef := NewConstructorReference(arrayTypeName, typeIdentifier, arguments, newToken.StartPos, endPos)
+	constructorRef.ArrayDimensions = make([]SpelNode, dimensionCount)
 	p.push(constructorRef)
 	return true
 }
@@ -1614,20 +1776,14 @@ func (p *InternalSpelExpressionParser) tryParseArrayConstructorWithSizes(newToke
 		}
 
 		// Parse the size expression inside brackets
-		sizeExpr, err := p.eatExpression()
-		if err != nil || sizeExpr == nil {
-			// Failed to parse size expression, revert
-			p.TokenStreamPointer = originalPosition
-			return false
+		sizeExpr, err := p.eatRequiredExpression()
+		if err != nil {
+			return p.recordError(err)
 		}
 
-		if !p.peekToken(RSQUARE) {
-			// Missing closing bracket, revert
-			p.TokenStreamPointer = originalPosition
-			return false
+		if _, err := p.eatToken(RSQUARE); err != nil {
+			return p.recordError(err)
 		}
-
-		p.takeToken() // consume ']'
 		sizeExpressions = append(sizeExpressions, sizeExpr)
 	}
 
@@ -1665,11 +1821,9 @@ func (p *InternalSpelExpressionParser) tryParseArrayConstructorWithSizes(newToke
 		} else {
 			// Parse elements separated by commas
 			for {
-				element, err := p.eatExpression()
-				if err != nil || element == nil {
-					// Failed to parse element, revert
-					p.TokenStreamPointer = originalPosition
-					return false
+				element, err := p.eatRequiredExpression()
+				if err != nil {
+					return p.recordError(err)
 				}
 				elements = append(elements, element)
 
@@ -1680,12 +1834,9 @@ func (p *InternalSpelExpressionParser) tryParseArrayConstructorWithSizes(newToke
 				break
 			}
 
-			if !p.peekToken(RCURLY) {
-				// Missing closing brace, revert
-				p.TokenStreamPointer = originalPosition
-				return false
+			if _, err := p.eatToken(RCURLY); err != nil {
+				return p.recordError(err)
 			}
-			p.takeToken() // consume '}'
 		}
 
 		endToken := p.TokenStream[p.TokenStreamPointer-1]
@@ -1714,6 +1865,7 @@ func (p *InternalSpelExpressionParser) tryParseArrayConstructorWithSizes(newToke
 	// Create ConstructorReference with custom display format
 	// Size expressions are used for parsing but not retained in AST (matching Java Spring SpEL behavior)
 	constructorRef := NewConstructorReferenceWithDisplay(typeName, typeIdentifier, arguments, displayFormat, newToken.StartPos, endPos)
+	constructorRef.ArrayDimensions = sizeExpressions
 	p.push(constructorRef)
 	return true
 }
@@ -1738,9 +1890,7 @@ func (p *InternalSpelExpressionParser) maybeEatTypeReference() bool {
 	var typeParts []string
 
 	if !p.peekToken(IDENTIFIER) {
-		// Not a valid type reference, put back tokens
-		p.TokenStreamPointer = tToken.StartPos
-		return false
+		return p.recordError(p.raiseNotExpectedToken(IDENTIFIER))
 	}
 
 	// Collect type name parts
@@ -1769,23 +1919,17 @@ func (p *InternalSpelExpressionParser) maybeEatTypeReference() bool {
 	// Handle array dimensions like String[], String[][], etc.
 	for p.peekToken(LSQUARE) {
 		p.takeToken() // consume '['
-		if !p.peekToken(RSQUARE) {
-			// Not a valid array type, put back tokens
-			p.TokenStreamPointer = tToken.StartPos
-			return false
+		if _, err := p.eatToken(RSQUARE); err != nil {
+			return p.recordError(err)
 		}
-		p.takeToken() // consume ']'
 		typeName += "[]"
 	}
 
-	if !p.peekToken(RPAREN) {
-		// Not a valid type reference, put back tokens
-		p.TokenStreamPointer = tToken.StartPos
-		return false
+	endToken, err := p.eatToken(RPAREN)
+	if err != nil {
+		return p.recordError(err)
 	}
 
-	endToken := p.takeToken() // consume ')'
-
 	typeRef := NewTypeReference(typeName, tToken.StartPos, endToken.EndPos)
 	p.push(typeRef)
 	return true
@@ -1796,6 +1940,10 @@ type SpelExpression struct {
 	ExpressionString string
 	AST              SpelNode
 	Configuration    *SpelParserConfiguration
+
+	// source locates lines and columns in ExpressionString, built on first use
+	// unless the parser provides it
+	source *SourceText
 }
 
 func NewSpelExpression(expression string, ast SpelNode, config *SpelParserConfiguration) *SpelExpression {
@@ -1808,12 +1956,96 @@ func NewSpelExpression(expression string, ast SpelNode, config *SpelParserConfig
 
 func (expr *SpelExpression) GetValue() (interface{}, error) {
 	state := NewExpressionState(expr.Configuration)
-	return expr.AST.GetValue(state)
+	value, err := expr.AST.GetValue(state)
+	return value, expr.evaluationError(err)
 }
 
 func (expr *SpelExpression) GetValueWithRoot(rootObject interface{}) (interface{}, error) {
 	state := NewExpressionStateWithRoot(expr.Configuration, NewTypedValue(rootObject))
-	return expr.AST.GetValue(state)
+	value, err := expr.AST.GetValue(state)
+	return value, expr.evaluationError(err)
+}
+
+// GetValueWithContext evaluates the expression against the given evaluation context,
+// using the context's root object
+func (expr *SpelExpression) GetValueWithContext(context EvaluationContext) (interface{}, error) {
+	state := NewExpressionStateWithContext(context, nil, expr.Configuration)
+	value, err := expr.AST.GetValue(state)
+	return value, expr.evaluationError(err)
+}
+
+// GetValueWithContextAndRoot evaluates the expression against the given evaluation context,
+// using rootObject in place of the context's root object
+func (expr *SpelExpression) GetValueWithContextAndRoot(context EvaluationContext, rootObject interface{}) (interface{}, error) {
+	state := NewExpressionStateWithContext(context, NewTypedValue(rootObject), expr.Configuration)
+	value, err := expr.AST.GetValue(state)
+	return value, expr.evaluationError(err)
+}
+
+// GetValueAs evaluates the expression against the given evaluation context and
+// converts the result to T with the context's type converter, so that
+// GetValueAs[int](expr, ctx) accepts any number fitting into an int, or a
+// string holding one. If context is nil a standard context is used.
+func GetValueAs[T any](expr *SpelExpression, context EvaluationContext) (T, error) {
+	var result T
+	if context == nil {
+		context = NewStandardEvaluationContext()
+	}
+	value, err := expr.GetValueWithContext(context)
+	if err != nil {
+		return result, err
+	}
+
+	targetType := reflect.TypeOf((*T)(nil)).Elem()
+	converted, err := convertArgument(context.GetTypeConverter(), value, targetType)
+	if err != nil {
+		convertErr := NewSpelEvaluationException(expr.AST.GetStartPosition(), TYPE_CONVERSION_ERROR,
+			fmt.Sprintf("%T", value), targetType.String()).WithCause(err)
+		return result, expr.evaluationError(convertErr)
+	}
+	result, _ = converted.Interface().(T)
+	return result, nil
+}
+
+// SetValue assigns value to the target described by the expression, such as a
+// property, variable or indexed element. If rootObject is nil the context's root
+// object is used, and if context is nil a standard context is created.
+func (expr *SpelExpression) SetValue(context EvaluationContext, rootObject interface{}, value interface{}) error {
+	var root *TypedValue
+	if rootObject != nil {
+		root = NewTypedValue(rootObject)
+	}
+	state := NewExpressionStateWithContext(context, root, expr.Configuration)
+	return expr.evaluationError(expr.AST.SetValue(state, value))
+}
+
+// IsWritable returns true if SetValue can assign to the expression
+func (expr *SpelExpression) IsWritable(context EvaluationContext, rootObject interface{}) bool {
+	var root *TypedValue
+	if rootObject != nil {
+		root = NewTypedValue(rootObject)
+	}
+	state := NewExpressionStateWithContext(context, root, expr.Configuration)
+	return expr.AST.IsWritable(state)
+}
+
+// evaluationError fills in the expression string and span of a
+// SpelEvaluationException raised by a node of the expression
+func (expr *SpelExpression) evaluationError(err error) error {
+	var evalErr *SpelEvaluationException
+	if errors.As(err, &evalErr) && evalErr.ExpressionString == "" {
+		evalErr.ExpressionString = expr.ExpressionString
+		evalErr.Span = expr.sourceText().Span(evalErr.Position, evalErr.Position)
+	}
+	return err
+}
+
+// sourceText returns the SourceText of the expression string
+func (expr *SpelExpression) sourceText() *SourceText {
+	if expr.source == nil {
+		expr.source = NewSourceText(expr.ExpressionString)
+	}
+	return expr.source
 }
 
 func (expr *SpelExpression) ToStringAST() string {
@@ -1858,13 +2090,13 @@ func (parser *SpelExpressionParser) ParseExpressionWithContext(expressionString
 	}
 }
 
-// DoParseExpression parses expression with debug output (matching Java version)
+// DoParseExpression parses expression like ParseExpression (matching Java version)
 func (parser *SpelExpressionParser) DoParseExpression(expressionString string) (*SpelExpression, error) {
 	internalParser := NewInternalSpelExpressionParser(parser.Configuration)
 	return internalParser.DoParseExpression(expressionString)
 }
 
-// doParseExpression parses expression without debug output
+// doParseExpression parses a standard (non template) expression
 func (parser *SpelExpressionParser) doParseExpression(expressionString string, context *ParserContext) (*SpelExpression, error) {
 	internalParser := NewInternalSpelExpressionParser(parser.Configuration)
 	return internalParser.ParseExpression(expressionString)
@@ -1881,33 +2113,42 @@ func (parser *SpelExpressionParser) parseTemplate(expressionString string, conte
 		return nil, err
 	}
 
-	if len(templateParts) == 1 && templateParts[0].IsLiteral {
-		// Pure literal template, no expressions
-		literal := NewStringLiteral(templateParts[0].Content, 0, len(expressionString))
-		return NewSpelExpression(expressionString, literal, parser.Configuration), nil
+	// Positions of all parts are character offsets into the whole template
+	source := NewSourceText(expressionString)
+	runeOffset := func(byteOffset int) int {
+		return utf8.RuneCountInString(expressionString[:byteOffset])
 	}
 
-	// Create composite expression for template
-	var nodes []SpelNode
-	for _, part := range templateParts {
-		if part.IsLiteral {
-			// Literal text part
-			literal := NewStringLiteral(part.Content, part.StartPos, part.EndPos)
-			nodes = append(nodes, literal)
-		} else {
-			// SpEL expression part
-			internalParser := NewInternalSpelExpressionParser(parser.Configuration)
-			exprNode, err := internalParser.ParseExpression(part.Content)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse expression '%s' in template: %v", part.Content, err)
+	var root SpelNode
+	if len(templateParts) == 1 && templateParts[0].IsLiteral {
+		// Pure literal template, no expressions
+		root = NewStringLiteral(templateParts[0].Content, 0, runeOffset(len(expressionString)))
+	} else {
+		// Create composite expression for template
+		var nodes []SpelNode
+		for _, part := range templateParts {
+			if part.IsLiteral {
+				// Literal text part
+				literal := NewStringLiteral(part.Content, runeOffset(part.StartPos), runeOffset(part.EndPos))
+				nodes = append(nodes, literal)
+			} else {
+				// SpEL expression part, parsed in place so that its positions refer to the template
+				internalParser := NewInternalSpelExpressionParser(parser.Configuration)
+				contentStart := runeOffset(part.StartPos + len(context.ExpressionPrefix))
+				exprNode, err := internalParser.parseExpression(source, part.Content, contentStart)
+				if err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, exprNode.AST)
 			}
-			nodes = append(nodes, exprNode.AST)
 		}
+		root = NewTemplateExpression(nodes, 0, runeOffset(len(expressionString)))
 	}
 
-	// Create template expression
-	templateExpr := NewTemplateExpression(nodes, 0, len(expressionString))
-	return NewSpelExpression(expressionString, templateExpr, parser.Configuration), nil
+	assignSourceSpans(source, root)
+	expr := NewSpelExpression(expressionString, root, parser.Configuration)
+	expr.source = source
+	return expr, nil
 }
 
 func (parser *SpelExpressionParser) ParseAST() (SpelNode, error) {
@@ -1972,14 +2213,16 @@ func (parser *SpelExpressionParser) parseTemplateExpression(template string, con
 		if (context.ExpressionPrefix == "#{" || context.ExpressionPrefix == "${") && context.ExpressionSuffix == "}" {
 			exprContent = parser.extractFromBraces(template, exprContentStart)
 			if exprContent == "" {
-				return nil, fmt.Errorf("no closing '%s' for expression starting at position %d", context.ExpressionSuffix, exprStart)
+				return nil, NewSpelParseException(template, utf8.RuneCountInString(template[:exprStart]),
+					MISSING_CHARACTER, context.ExpressionSuffix)
 			}
 			exprEnd = exprContentStart + len(exprContent)
 		} else {
 			// For other formats, use simple string search
 			suffixPos := strings.Index(template[exprContentStart:], context.ExpressionSuffix)
 			if suffixPos == -1 {
-				return nil, fmt.Errorf("no closing '%s' for expression starting at position %d", context.ExpressionSuffix, exprStart)
+				return nil, NewSpelParseException(template, utf8.RuneCountInString(template[:exprStart]),
+					MISSING_CHARACTER, context.ExpressionSuffix)
 			}
 			exprEnd = exprContentStart + suffixPos
 			exprContent = template[exprContentStart:exprEnd]
@@ -0,0 +1,336 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PropertyAccessor reads and writes named properties of target objects. Accessors
+// are consulted in order by PropertyOrFieldReference until one can handle the property.
+type PropertyAccessor interface {
+	// CanRead returns true if the named property can be read from target
+	CanRead(context EvaluationContext, target interface{}, name string) bool
+
+	// Read returns the value of the named property of target
+	Read(context EvaluationContext, target interface{}, name string) (interface{}, error)
+
+	// CanWrite returns true if the named property of target can be assigned
+	CanWrite(context EvaluationContext, target interface{}, name string) bool
+
+	// Write assigns newValue to the named property of target
+	Write(context EvaluationContext, target interface{}, name string, newValue interface{}) error
+}
+
+// ReflectivePropertyAccessor reads exported struct fields, getter-style methods
+// (GetName, Name, IsActive) and string keyed map entries. Pointers and interfaces
+// are unwrapped and names are matched case-insensitively, so the Java style
+// "user.name" resolves against a Go User.Name field. On a *TypeDescriptor, the
+// value of T(...), the constants registered for the type are read instead and
+// nothing can be written. Writes go through setter
+// methods (SetName), exported fields of structs reached through a pointer, and
+// map entries.
+type ReflectivePropertyAccessor struct{}
+
+// NewReflectivePropertyAccessor creates the default property accessor
+func NewReflectivePropertyAccessor() *ReflectivePropertyAccessor {
+	return &ReflectivePropertyAccessor{}
+}
+
+func (a *ReflectivePropertyAccessor) CanRead(context EvaluationContext, target interface{}, name string) bool {
+	_, ok := a.findProperty(target, name)
+	return ok
+}
+
+func (a *ReflectivePropertyAccessor) Read(context EvaluationContext, target interface{}, name string) (interface{}, error) {
+	read, ok := a.findProperty(target, name)
+	if !ok {
+		return nil, fmt.Errorf("property or field '%s' cannot be found on object of type '%T'", name, target)
+	}
+	return read()
+}
+
+func (a *ReflectivePropertyAccessor) CanWrite(context EvaluationContext, target interface{}, name string) bool {
+	_, ok := a.findWritableProperty(target, name)
+	return ok
+}
+
+func (a *ReflectivePropertyAccessor) Write(context EvaluationContext, target interface{}, name string, newValue interface{}) error {
+	write, ok := a.findWritableProperty(target, name)
+	if !ok {
+		return fmt.Errorf("property or field '%s' cannot be set on object of type '%T'", name, target)
+	}
+	var converter TypeConverter
+	if context != nil {
+		converter = context.GetTypeConverter()
+	}
+	return write(converter, newValue)
+}
+
+// findWritableProperty locates the named property on target and returns a
+// function assigning it
+func (a *ReflectivePropertyAccessor) findWritableProperty(target interface{}, name string) (func(TypeConverter, interface{}) error, bool) {
+	if _, ok := target.(*TypeDescriptor); ok || target == nil {
+		return nil, false
+	}
+
+	original := reflect.ValueOf(target)
+	value, ok := unwrapValue(original)
+	if !ok {
+		return nil, false
+	}
+
+	if value.Kind() == reflect.Map {
+		if value.IsNil() {
+			return nil, false
+		}
+		key, ok := findMapKey(value, name)
+		if !ok {
+			if !reflect.TypeOf(name).ConvertibleTo(value.Type().Key()) {
+				return nil, false
+			}
+			key = reflect.ValueOf(name).Convert(value.Type().Key())
+		}
+		return func(converter TypeConverter, newValue interface{}) error {
+			converted, err := convertArgument(converter, newValue, value.Type().Elem())
+			if err != nil {
+				return err
+			}
+			value.SetMapIndex(key, converted)
+			return nil
+		}, true
+	}
+
+	if setter, ok := findSetter(original, name); ok {
+		return func(converter TypeConverter, newValue interface{}) error {
+			converted, err := convertArgument(converter, newValue, setter.Type().In(0))
+			if err != nil {
+				return err
+			}
+			_, err = invoke(setter, []reflect.Value{converted}, false, fmt.Sprintf("setter of property '%s'", name))
+			return err
+		}, true
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	// Fields can only be assigned when the struct was reached through a pointer
+	field, ok := findField(value, name)
+	if !ok || !field.CanSet() {
+		return nil, false
+	}
+	return func(converter TypeConverter, newValue interface{}) error {
+		converted, err := convertArgument(converter, newValue, field.Type())
+		if err != nil {
+			return err
+		}
+		field.Set(converted)
+		return nil
+	}, true
+}
+
+// findSetter finds a setter method for the named property. Setters take a single
+// argument and return nothing or an error.
+func findSetter(value reflect.Value, name string) (reflect.Value, bool) {
+	if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return reflect.Value{}, false
+	}
+
+	typ := value.Type()
+	for i := 0; i < typ.NumMethod(); i++ {
+		method := typ.Method(i)
+		if !strings.EqualFold(method.Name, "Set"+name) || method.Type.NumIn() != 2 {
+			continue
+		}
+		if method.Type.NumOut() == 0 || (method.Type.NumOut() == 1 && method.Type.Out(0) == errorType) {
+			return value.Method(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// findProperty locates the named property on target and returns a function reading it
+func (a *ReflectivePropertyAccessor) findProperty(target interface{}, name string) (func() (interface{}, error), bool) {
+	if target == nil {
+		return nil, false
+	}
+	if descriptor, ok := target.(*TypeDescriptor); ok {
+		constant, ok := descriptor.LookupConstant(name)
+		if !ok {
+			return nil, false
+		}
+		return func() (interface{}, error) { return constant, nil }, true
+	}
+
+	original := reflect.ValueOf(target)
+	value, ok := unwrapValue(original)
+	if !ok {
+		return nil, false
+	}
+
+	if value.Kind() == reflect.Map {
+		key, ok := findMapKey(value, name)
+		if !ok {
+			return nil, false
+		}
+		return func() (interface{}, error) {
+			return valueToInterface(value.MapIndex(key)), nil
+		}, true
+	}
+	// Arrays expose their length like Java arrays do
+	if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && name == "length" {
+		return func() (interface{}, error) { return value.Len(), nil }, true
+	}
+
+	// Getter methods may be declared on the pointer or on the value
+	if getter, ok := findGetter(original, name); ok {
+		return func() (interface{}, error) { return callGetter(getter, name) }, true
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+	if getter, ok := findGetter(addressableCopy(value), name); ok {
+		return func() (interface{}, error) { return callGetter(getter, name) }, true
+	}
+
+	field, ok := findField(value, name)
+	if !ok {
+		return nil, false
+	}
+	return func() (interface{}, error) {
+		return valueToInterface(field), nil
+	}, true
+}
+
+// unwrapValue dereferences pointers and interfaces, returning false on nil
+func unwrapValue(value reflect.Value) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return value, false
+		}
+		value = value.Elem()
+	}
+	return value, value.IsValid()
+}
+
+// addressableCopy returns an addressable pointer to value so that methods with
+// pointer receivers can be found on values that were passed by value
+func addressableCopy(value reflect.Value) reflect.Value {
+	if value.CanAddr() {
+		return value.Addr()
+	}
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr
+}
+
+// findGetter finds a getter method for the named property. Getters take no
+// arguments and return a value, optionally followed by an error.
+func findGetter(value reflect.Value, name string) (reflect.Value, bool) {
+	if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		return reflect.Value{}, false
+	}
+
+	candidates := []string{"Get" + name, "Is" + name, name}
+	typ := value.Type()
+	for _, candidate := range candidates {
+		for i := 0; i < typ.NumMethod(); i++ {
+			method := typ.Method(i)
+			if !strings.EqualFold(method.Name, candidate) || !isGetterType(method.Type) {
+				continue
+			}
+			return value.Method(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// isGetterType checks a method type (including receiver) has a getter signature
+func isGetterType(methodType reflect.Type) bool {
+	if methodType.NumIn() != 1 {
+		return false
+	}
+	switch methodType.NumOut() {
+	case 1:
+		return true
+	case 2:
+		return methodType.Out(1) == errorType
+	default:
+		return false
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// callGetter invokes the getter of the named property. A panic of the getter
+// is returned as an error.
+func callGetter(getter reflect.Value, name string) (interface{}, error) {
+	return invoke(getter, nil, false, fmt.Sprintf("getter of property '%s'", name))
+}
+
+// findField finds an exported struct field, including promoted fields, by case-insensitive name
+func findField(value reflect.Value, name string) (reflect.Value, bool) {
+	structField, ok := value.Type().FieldByNameFunc(func(fieldName string) bool {
+		return strings.EqualFold(fieldName, name)
+	})
+	if !ok || !structField.IsExported() {
+		return reflect.Value{}, false
+	}
+	field, err := value.FieldByIndexErr(structField.Index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	return field, true
+}
+
+// findMapKey finds the key for a property name in a map with string keys,
+// preferring an exact match over a case-insensitive one
+func findMapKey(value reflect.Value, name string) (reflect.Value, bool) {
+	keyType := value.Type().Key()
+	if keyType.Kind() != reflect.String && keyType.Kind() != reflect.Interface {
+		return reflect.Value{}, false
+	}
+
+	if keyType.Kind() == reflect.String {
+		key := reflect.ValueOf(name).Convert(keyType)
+		if value.MapIndex(key).IsValid() {
+			return key, true
+		}
+	} else if value.MapIndex(reflect.ValueOf(name)).IsValid() {
+		return reflect.ValueOf(name), true
+	}
+
+	iter := value.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if key.Kind() == reflect.String && strings.EqualFold(key.String(), name) {
+			return iter.Key(), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// valueToInterface converts a reflect.Value to interface{}, mapping invalid
+// values and nil interfaces to nil
+func valueToInterface(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+	if value.Kind() == reflect.Interface && value.IsNil() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// isNull reports whether value is nil or a nil pointer, map, slice or interface
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return isNillableType(v.Type()) && v.IsNil()
+}
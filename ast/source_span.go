@@ -0,0 +1,124 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SourcePosition locates a character of an expression. Offset counts runes
+// from the start of the expression, Line and Column count from 1.
+type SourcePosition struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p SourcePosition) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// SourceSpan is the range of an expression covered by a token or node. End
+// is the position just after the last character.
+type SourceSpan struct {
+	Start SourcePosition
+	End   SourcePosition
+}
+
+func (s SourceSpan) String() string {
+	return fmt.Sprintf("%s-%s", s.Start, s.End)
+}
+
+// SourceText maps rune offsets of an expression to lines and columns. It is
+// built once per expression and shared by its tokens, nodes and errors.
+type SourceText struct {
+	text        string
+	length      int
+	lineStarts  []int // rune offset of the first character of each line
+	byteOffsets []int // byte offset of each rune offset, built on first use by Text
+}
+
+func NewSourceText(text string) *SourceText {
+	source := &SourceText{text: text, lineStarts: []int{0}}
+	offset := 0
+	for _, r := range text {
+		offset++
+		if r == '\n' {
+			source.lineStarts = append(source.lineStarts, offset)
+		}
+	}
+	source.length = offset
+	return source
+}
+
+// String returns the text of the expression
+func (s *SourceText) String() string {
+	return s.text
+}
+
+// Position returns the line and column of offset, which is clamped to the text
+func (s *SourceText) Position(offset int) SourcePosition {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > s.length {
+		offset = s.length
+	}
+	line := sort.Search(len(s.lineStarts), func(i int) bool { return s.lineStarts[i] > offset })
+	return SourcePosition{
+		Offset: offset,
+		Line:   line,
+		Column: offset - s.lineStarts[line-1] + 1,
+	}
+}
+
+// Text returns the characters between the rune offsets start and end, which
+// are clamped to the text. The result is a substring of the text rather than
+// a copy.
+func (s *SourceText) Text(start, end int) string {
+	end = min(max(end, 0), s.length)
+	start = min(max(start, 0), end)
+	if s.length == len(s.text) {
+		return s.text[start:end]
+	}
+	if s.byteOffsets == nil {
+		s.byteOffsets = make([]int, 0, s.length+1)
+		for offset := range s.text {
+			s.byteOffsets = append(s.byteOffsets, offset)
+		}
+		s.byteOffsets = append(s.byteOffsets, len(s.text))
+	}
+	return s.text[s.byteOffsets[start]:s.byteOffsets[end]]
+}
+
+// Span returns the span between the rune offsets start and end
+func (s *SourceText) Span(start, end int) SourceSpan {
+	return SourceSpan{Start: s.Position(start), End: s.Position(end)}
+}
+
+// describePosition renders position for an error message, adding the line and
+// column when the expression spans several lines. A zero span is computed from
+// the expression.
+func describePosition(expression string, position int, span SourceSpan) string {
+	if strings.ContainsRune(expression, '\n') {
+		start := span.Start
+		if start.Line == 0 {
+			start = NewSourceText(expression).Position(position)
+		}
+		return fmt.Sprintf("position %d, line %d, column %d", position, start.Line, start.Column)
+	}
+	return fmt.Sprintf("position %d", position)
+}
+
+// assignSourceSpans sets the span of node and its descendants from their positions
+func assignSourceSpans(source *SourceText, node SpelNode) {
+	if node == nil {
+		return
+	}
+	if spanned, ok := node.(interface{ setSourceSpan(SourceSpan) }); ok {
+		spanned.setSourceSpan(source.Span(node.GetStartPosition(), node.GetEndPosition()))
+	}
+	for _, child := range node.GetChildren() {
+		assignSourceSpans(source, child)
+	}
+}
@@ -0,0 +1,328 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SpelMessage identifies the kind of a SpelParseException or
+// SpelEvaluationException. The codes mirror Spring's SpelMessage, so callers
+// can branch on them with errors.As.
+type SpelMessage int
+
+const (
+	// Parse messages
+	MAX_EXPRESSION_LENGTH_EXCEEDED SpelMessage = iota + 1
+	OOD
+	MORE_INPUT
+	LEFT_OPERAND_PROBLEM
+	RIGHT_OPERAND_PROBLEM
+	NOT_EXPECTED_TOKEN
+	MISSING_CHARACTER
+	NON_TERMINATING_QUOTED_STRING
+	NON_TERMINATING_DOUBLE_QUOTED_STRING
+	UNEXPECTED_ESCAPE_CHAR
+	UNSUPPORTED_CHARACTER
+	REAL_CANNOT_BE_LONG
+	NOT_AN_INTEGER
+	NOT_A_LONG
+	NOT_A_REAL
+	UNEXPECTED_DATA_AFTER_DOT
+	MISSING_CONSTRUCTOR_ARGS
+	MISSING_ARRAY_DIMENSION
+	INVALID_BEAN_REFERENCE
+	RUN_OUT_OF_ARGUMENTS
+	MISSING_SELECTION_EXPRESSION
+	MAX_NESTING_DEPTH_EXCEEDED
+	MAX_NODE_COUNT_EXCEEDED
+	MAX_TOKEN_COUNT_EXCEEDED
+	MAX_STRING_LITERAL_LENGTH_EXCEEDED
+
+	// Evaluation messages
+	TYPE_CONVERSION_ERROR
+	OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES
+	NOT_COMPARABLE
+	NOT_ASSIGNABLE
+	MAX_REPEATED_TEXT_SIZE_EXCEEDED
+	NEGATIVE_REPEATED_TEXT_COUNT
+	INSTANCEOF_OPERATOR_NEEDS_CLASS_OPERAND
+	NO_BEAN_RESOLVER_REGISTERED
+	EXCEPTION_DURING_BEAN_RESOLUTION
+	CANNOT_INDEX_INTO_NULL_VALUE
+	INDEXING_NOT_SUPPORTED_FOR_TYPE
+	INVALID_TYPE_FOR_SELECTION
+	RESULT_OF_SELECTION_CRITERIA_IS_NOT_BOOLEAN
+	PROJECTION_NOT_SUPPORTED_ON_TYPE
+	FUNCTION_NOT_DEFINED
+	INCORRECT_NUMBER_OF_ARGUMENTS_TO_FUNCTION
+	EXCEPTION_DURING_FUNCTION_CALL
+	TYPE_NOT_FOUND
+	CONSTRUCTOR_NOT_FOUND
+	MAX_ARRAY_ELEMENTS_THRESHOLD_EXCEEDED
+	UNHASHABLE_MAP_KEY
+	DIVISION_BY_ZERO
+	PROPERTY_OR_FIELD_NOT_READABLE
+	PROPERTY_OR_FIELD_NOT_READABLE_ON_NULL
+	PROPERTY_OR_FIELD_NOT_WRITABLE
+	PROPERTY_OR_FIELD_NOT_WRITABLE_ON_NULL
+	METHOD_NOT_FOUND
+	METHOD_CALL_ON_NULL_OBJECT_NOT_ALLOWED
+	OPERAND_NOT_INCREMENTABLE
+	OPERAND_NOT_DECREMENTABLE
+	EXCEPTION_DURING_PROPERTY_READ
+	EXCEPTION_DURING_PROPERTY_WRITE
+	PROBLEM_LOCATING_METHOD
+	MULTIPLE_POSSIBLE_METHODS
+	EXCEPTION_DURING_METHOD_INVOCATION
+	CONSTRUCTOR_INVOCATION_PROBLEM
+	ARRAY_INDEX_OUT_OF_BOUNDS
+	STRING_INDEX_OUT_OF_BOUNDS
+	INVALID_PATTERN
+	BETWEEN_RIGHT_OPERAND_MUST_BE_TWO_ELEMENT_LIST
+	INITIALIZER_LENGTH_INCORRECT
+	NEGATIVE_ARRAY_DIMENSION
+	ARRAY_ELEMENT_TYPE_NOT_SUPPORTED
+	MISSING_INDEX_EXPRESSION
+	MISSING_PROJECTION_EXPRESSION
+)
+
+// spelMessages holds the name and the message format of each code. The
+// format is applied to the inserts of the exception.
+var spelMessages = map[SpelMessage]struct {
+	name   string
+	format string
+}{
+	MAX_EXPRESSION_LENGTH_EXCEEDED:       {"MAX_EXPRESSION_LENGTH_EXCEEDED", "expression exceeds maximum length of %d"},
+	OOD:                                  {"OOD", "unexpectedly ran out of input"},
+	MORE_INPUT:                           {"MORE_INPUT", "after parsing a valid expression, there is still more data in the expression: '%s'"},
+	LEFT_OPERAND_PROBLEM:                 {"LEFT_OPERAND_PROBLEM", "problem parsing left operand of '%s'"},
+	RIGHT_OPERAND_PROBLEM:                {"RIGHT_OPERAND_PROBLEM", "problem parsing right operand of '%s'"},
+	NOT_EXPECTED_TOKEN:                   {"NOT_EXPECTED_TOKEN", "unexpected token, expected '%s' but was '%s'"},
+	MISSING_CHARACTER:                    {"MISSING_CHARACTER", "missing expected character '%s'"},
+	NON_TERMINATING_QUOTED_STRING:        {"NON_TERMINATING_QUOTED_STRING", "cannot find terminating ' for string"},
+	NON_TERMINATING_DOUBLE_QUOTED_STRING: {"NON_TERMINATING_DOUBLE_QUOTED_STRING", "cannot find terminating \" for string"},
+	UNEXPECTED_ESCAPE_CHAR:               {"UNEXPECTED_ESCAPE_CHAR", "unexpected escape character"},
+	UNSUPPORTED_CHARACTER:                {"UNSUPPORTED_CHARACTER", "unsupported character '%c' (%d) encountered in expression"},
+	REAL_CANNOT_BE_LONG:                  {"REAL_CANNOT_BE_LONG", "real number cannot be suffixed with a long (L or l) suffix"},
+	NOT_AN_INTEGER:                       {"NOT_AN_INTEGER", "the value '%s' cannot be parsed as an int"},
+	NOT_A_LONG:                           {"NOT_A_LONG", "the value '%s' cannot be parsed as a long"},
+	NOT_A_REAL:                           {"NOT_A_REAL", "the value '%s' cannot be parsed as a double"},
+	UNEXPECTED_DATA_AFTER_DOT:            {"UNEXPECTED_DATA_AFTER_DOT", "unexpected data after '.': '%s'"},
+	MISSING_CONSTRUCTOR_ARGS:             {"MISSING_CONSTRUCTOR_ARGS", "the arguments '(...)' for the constructor call are missing"},
+	MISSING_ARRAY_DIMENSION:              {"MISSING_ARRAY_DIMENSION", "a required array dimension has not been specified"},
+	INVALID_BEAN_REFERENCE:               {"INVALID_BEAN_REFERENCE", "'@' or '&' must be followed by an identifier or a quoted string"},
+	RUN_OUT_OF_ARGUMENTS:                 {"RUN_OUT_OF_ARGUMENTS", "unexpectedly ran out of arguments"},
+	MISSING_SELECTION_EXPRESSION:         {"MISSING_SELECTION_EXPRESSION", "a required selection expression has not been specified"},
+	MAX_NESTING_DEPTH_EXCEEDED:           {"MAX_NESTING_DEPTH_EXCEEDED", "expression is nested too deeply, exceeding the maximum depth of %d"},
+	MAX_NODE_COUNT_EXCEEDED:              {"MAX_NODE_COUNT_EXCEEDED", "expression has too many nodes, exceeding the maximum of %d"},
+	MAX_TOKEN_COUNT_EXCEEDED:             {"MAX_TOKEN_COUNT_EXCEEDED", "expression has too many tokens, exceeding the maximum of %d"},
+	MAX_STRING_LITERAL_LENGTH_EXCEEDED:   {"MAX_STRING_LITERAL_LENGTH_EXCEEDED", "string literal exceeds maximum length of %d"},
+
+	TYPE_CONVERSION_ERROR:                          {"TYPE_CONVERSION_ERROR", "type conversion problem, cannot convert from %s to %s"},
+	OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES:           {"OPERATOR_NOT_SUPPORTED_BETWEEN_TYPES", "operator '%s' is not supported between objects of type '%T' and '%T'"},
+	NOT_COMPARABLE:                                 {"NOT_COMPARABLE", "cannot compare instances of %T and %T"},
+	NOT_ASSIGNABLE:                                 {"NOT_ASSIGNABLE", "cannot assign to the expression"},
+	MAX_REPEATED_TEXT_SIZE_EXCEEDED:                {"MAX_REPEATED_TEXT_SIZE_EXCEEDED", "repeated text is too long, exceeding the threshold of %d characters"},
+	NEGATIVE_REPEATED_TEXT_COUNT:                   {"NEGATIVE_REPEATED_TEXT_COUNT", "cannot repeat text a negative number of times: %d"},
+	INSTANCEOF_OPERATOR_NEEDS_CLASS_OPERAND:        {"INSTANCEOF_OPERATOR_NEEDS_CLASS_OPERAND", "right operand for the 'instanceof' operator must be a type, not '%T'"},
+	NO_BEAN_RESOLVER_REGISTERED:                    {"NO_BEAN_RESOLVER_REGISTERED", "no bean resolver registered in the context to resolve access to bean '%s'"},
+	EXCEPTION_DURING_BEAN_RESOLUTION:               {"EXCEPTION_DURING_BEAN_RESOLUTION", "exception thrown when resolving bean '%s'"},
+	CANNOT_INDEX_INTO_NULL_VALUE:                   {"CANNOT_INDEX_INTO_NULL_VALUE", "cannot index into a null value"},
+	INDEXING_NOT_SUPPORTED_FOR_TYPE:                {"INDEXING_NOT_SUPPORTED_FOR_TYPE", "indexing into type '%T' is not supported"},
+	INVALID_TYPE_FOR_SELECTION:                     {"INVALID_TYPE_FOR_SELECTION", "cannot perform selection on input data of type '%s'"},
+	RESULT_OF_SELECTION_CRITERIA_IS_NOT_BOOLEAN:    {"RESULT_OF_SELECTION_CRITERIA_IS_NOT_BOOLEAN", "result of selection criteria is not boolean: %T"},
+	PROJECTION_NOT_SUPPORTED_ON_TYPE:               {"PROJECTION_NOT_SUPPORTED_ON_TYPE", "projection is not supported on the type '%s'"},
+	FUNCTION_NOT_DEFINED:                           {"FUNCTION_NOT_DEFINED", "function '%s' could not be found"},
+	INCORRECT_NUMBER_OF_ARGUMENTS_TO_FUNCTION:      {"INCORRECT_NUMBER_OF_ARGUMENTS_TO_FUNCTION", "incorrect number of arguments for function '%s': %d supplied but function takes %d"},
+	EXCEPTION_DURING_FUNCTION_CALL:                 {"EXCEPTION_DURING_FUNCTION_CALL", "cannot invoke function '%s'"},
+	TYPE_NOT_FOUND:                                 {"TYPE_NOT_FOUND", "type cannot be found '%s'"},
+	CONSTRUCTOR_NOT_FOUND:                          {"CONSTRUCTOR_NOT_FOUND", "constructor call: no suitable constructor found on type '%s' for arguments (%s)"},
+	MAX_ARRAY_ELEMENTS_THRESHOLD_EXCEEDED:          {"MAX_ARRAY_ELEMENTS_THRESHOLD_EXCEEDED", "array declares too many elements, exceeding the threshold of %d"},
+	UNHASHABLE_MAP_KEY:                             {"UNHASHABLE_MAP_KEY", "a value of type '%T' cannot be used as a map key"},
+	DIVISION_BY_ZERO:                               {"DIVISION_BY_ZERO", "division by zero"},
+	PROPERTY_OR_FIELD_NOT_READABLE:                 {"PROPERTY_OR_FIELD_NOT_READABLE", "property or field '%s' cannot be found on object of type '%T'"},
+	PROPERTY_OR_FIELD_NOT_READABLE_ON_NULL:         {"PROPERTY_OR_FIELD_NOT_READABLE_ON_NULL", "property or field '%s' cannot be found on null"},
+	PROPERTY_OR_FIELD_NOT_WRITABLE:                 {"PROPERTY_OR_FIELD_NOT_WRITABLE", "property or field '%s' cannot be set on object of type '%T'"},
+	PROPERTY_OR_FIELD_NOT_WRITABLE_ON_NULL:         {"PROPERTY_OR_FIELD_NOT_WRITABLE_ON_NULL", "property or field '%s' cannot be set on null"},
+	METHOD_NOT_FOUND:                               {"METHOD_NOT_FOUND", "method call: method %s cannot be found on type '%T'"},
+	METHOD_CALL_ON_NULL_OBJECT_NOT_ALLOWED:         {"METHOD_CALL_ON_NULL_OBJECT_NOT_ALLOWED", "method call: attempted to call method %s on null context object"},
+	OPERAND_NOT_INCREMENTABLE:                      {"OPERAND_NOT_INCREMENTABLE", "the expression component '%s' does not support increment"},
+	OPERAND_NOT_DECREMENTABLE:                      {"OPERAND_NOT_DECREMENTABLE", "the expression component '%s' does not support decrement"},
+	EXCEPTION_DURING_PROPERTY_READ:                 {"EXCEPTION_DURING_PROPERTY_READ", "a problem occurred whilst attempting to access the property '%s'"},
+	EXCEPTION_DURING_PROPERTY_WRITE:                {"EXCEPTION_DURING_PROPERTY_WRITE", "a problem occurred whilst attempting to set the property '%s'"},
+	PROBLEM_LOCATING_METHOD:                        {"PROBLEM_LOCATING_METHOD", "problem locating method %s on type '%T'"},
+	MULTIPLE_POSSIBLE_METHODS:                      {"MULTIPLE_POSSIBLE_METHODS", "method call of '%s' is ambiguous, supported type conversions allow multiple variants to match"},
+	EXCEPTION_DURING_METHOD_INVOCATION:             {"EXCEPTION_DURING_METHOD_INVOCATION", "a problem occurred whilst attempting to invoke method '%s' on object of type '%T'"},
+	CONSTRUCTOR_INVOCATION_PROBLEM:                 {"CONSTRUCTOR_INVOCATION_PROBLEM", "a problem occurred whilst attempting to construct an object of type '%s'"},
+	ARRAY_INDEX_OUT_OF_BOUNDS:                      {"ARRAY_INDEX_OUT_OF_BOUNDS", "the array has '%d' elements, index '%d' is invalid"},
+	STRING_INDEX_OUT_OF_BOUNDS:                     {"STRING_INDEX_OUT_OF_BOUNDS", "the string has '%d' characters, index '%d' is invalid"},
+	INVALID_PATTERN:                                {"INVALID_PATTERN", "pattern is not valid '%s'"},
+	BETWEEN_RIGHT_OPERAND_MUST_BE_TWO_ELEMENT_LIST: {"BETWEEN_RIGHT_OPERAND_MUST_BE_TWO_ELEMENT_LIST", "right operand for the 'between' operator has to be a two-element list"},
+	INITIALIZER_LENGTH_INCORRECT:                   {"INITIALIZER_LENGTH_INCORRECT", "array initializer size does not match array dimensions"},
+	NEGATIVE_ARRAY_DIMENSION:                       {"NEGATIVE_ARRAY_DIMENSION", "array dimension cannot be negative: %d"},
+	ARRAY_ELEMENT_TYPE_NOT_SUPPORTED:               {"ARRAY_ELEMENT_TYPE_NOT_SUPPORTED", "type '%s' cannot be used as an array element type"},
+	MISSING_INDEX_EXPRESSION:                       {"MISSING_INDEX_EXPRESSION", "a required index expression has not been specified"},
+	MISSING_PROJECTION_EXPRESSION:                  {"MISSING_PROJECTION_EXPRESSION", "a required projection expression has not been specified"},
+}
+
+// String returns the Spring name of the code, such as NOT_EXPECTED_TOKEN
+func (m SpelMessage) String() string {
+	if message, ok := spelMessages[m]; ok {
+		return message.name
+	}
+	return fmt.Sprintf("SpelMessage(%d)", int(m))
+}
+
+// FormatMessage renders the message text of the code with the given inserts
+func (m SpelMessage) FormatMessage(inserts ...interface{}) string {
+	message, ok := spelMessages[m]
+	if !ok {
+		return fmt.Sprint(append([]interface{}{m.String()}, inserts...)...)
+	}
+	if len(inserts) == 0 {
+		return message.format
+	}
+	return fmt.Sprintf(message.format, inserts...)
+}
+
+// SpelParseException is returned when an expression cannot be parsed. Position
+// is the offset in characters of the problem within ExpressionString, and Span
+// gives its line and column.
+type SpelParseException struct {
+	ExpressionString string
+	Position         int
+	Span             SourceSpan
+	Message          SpelMessage
+	Inserts          []interface{}
+}
+
+func NewSpelParseException(expressionString string, position int, message SpelMessage, inserts ...interface{}) *SpelParseException {
+	return newSpelParseException(NewSourceText(expressionString), position, message, inserts...)
+}
+
+// newSpelParseException creates the exception for an expression whose
+// SourceText is already known
+func newSpelParseException(source *SourceText, position int, message SpelMessage, inserts ...interface{}) *SpelParseException {
+	return &SpelParseException{
+		ExpressionString: source.String(),
+		Position:         position,
+		Span:             source.Span(position, position),
+		Message:          message,
+		Inserts:          inserts,
+	}
+}
+
+func (e *SpelParseException) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message.FormatMessage(e.Inserts...), describePosition(e.ExpressionString, e.Position, e.Span))
+}
+
+// FormatWithCaret renders the message followed by the expression with a caret
+// under the error position
+func (e *SpelParseException) FormatWithCaret() string {
+	return formatWithCaret(e.Error(), e.ExpressionString, e.Position)
+}
+
+// SpelEvaluationException is returned when a node fails to evaluate. Position
+// is the start of the failing node, or -1 if it was raised outside of a node,
+// e.g. by a resolver. ExpressionString and Span are filled in by the
+// SpelExpression that was evaluated. Cause, if set, is the underlying error and
+// is returned by Unwrap.
+type SpelEvaluationException struct {
+	ExpressionString string
+	Position         int
+	Span             SourceSpan
+	Message          SpelMessage
+	Inserts          []interface{}
+	Cause            error
+}
+
+func NewSpelEvaluationException(position int, message SpelMessage, inserts ...interface{}) *SpelEvaluationException {
+	return &SpelEvaluationException{
+		Position: position,
+		Message:  message,
+		Inserts:  inserts,
+	}
+}
+
+// WithCause sets the underlying error of the exception and returns it
+func (e *SpelEvaluationException) WithCause(cause error) *SpelEvaluationException {
+	e.Cause = cause
+	return e
+}
+
+func (e *SpelEvaluationException) Error() string {
+	message := e.Message.FormatMessage(e.Inserts...)
+	if e.Position != positionUnknown {
+		message += " (" + describePosition(e.ExpressionString, e.Position, e.Span) + ")"
+	}
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", message, e.Cause)
+	}
+	return message
+}
+
+func (e *SpelEvaluationException) Unwrap() error {
+	return e.Cause
+}
+
+// positionUnknown is the position of an exception raised outside of a node,
+// until the node that caused it is known
+const positionUnknown = -1
+
+// atPosition turns err, returned by a resolver, accessor or executor, into a
+// SpelEvaluationException of the node at position. An exception without a
+// position gets this one, other exceptions are kept and any other error
+// becomes the cause of a new exception with message and inserts.
+func atPosition(err error, position int, message SpelMessage, inserts ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	var spelErr *SpelEvaluationException
+	if errors.As(err, &spelErr) {
+		if spelErr.Position == positionUnknown {
+			spelErr.Position = position
+		}
+		return err
+	}
+	return NewSpelEvaluationException(position, message, inserts...).WithCause(err)
+}
+
+// FormatWithCaret renders the message followed by the expression with a caret
+// under the error position
+func (e *SpelEvaluationException) FormatWithCaret() string {
+	return formatWithCaret(e.Error(), e.ExpressionString, e.Position)
+}
+
+// formatWithCaret renders message, the line of expression holding position and
+// a caret under the character at position
+func formatWithCaret(message, expression string, position int) string {
+	runes := []rune(expression)
+	if position < 0 {
+		position = 0
+	}
+	if position > len(runes) {
+		position = len(runes)
+	}
+
+	lineStart := position
+	for lineStart > 0 && runes[lineStart-1] != '\n' {
+		lineStart--
+	}
+	lineEnd := position
+	for lineEnd < len(runes) && runes[lineEnd] != '\n' {
+		lineEnd++
+	}
+
+	// Tabs are kept in the caret line so that it lines up with the expression
+	var caret strings.Builder
+	for _, r := range runes[lineStart:position] {
+		if r == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+	return message + "\n" + string(runes[lineStart:lineEnd]) + "\n" + caret.String()
+}
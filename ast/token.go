@@ -9,6 +9,7 @@ type Token struct {
 	Data     *string // Nullable string pointer to match Java's @Nullable String
 	StartPos int
 	EndPos   int
+	Span     SourceSpan // line and column of StartPos and EndPos, set by the tokenizer
 }
 
 // NewToken creates a new token without data (for tokens like TRUE or '+')
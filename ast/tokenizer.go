@@ -1,7 +1,6 @@
 package ast
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 	"unicode"
@@ -10,10 +9,16 @@ import (
 // Tokenizer lexes input data into a stream of tokens that can then be parsed
 type Tokenizer struct {
 	expressionString string
+	source           *SourceText
 	charsToProcess   []rune
 	pos              int
 	max              int
 	tokens           []*Token
+
+	// maxTokenCount and maxStringLiteralLength are the limits of the parser
+	// configuration, zero when unlimited
+	maxTokenCount          int
+	maxStringLiteralLength int
 }
 
 // Alternative textual operator names which must match enum constant names in TokenKind
@@ -52,6 +57,7 @@ func NewTokenizer(inputData string) *Tokenizer {
 	charsToProcess := []rune(inputData + "\x00") // Add null terminator
 	return &Tokenizer{
 		expressionString: inputData,
+		source:           NewSourceText(inputData),
 		charsToProcess:   charsToProcess,
 		pos:              0,
 		max:              len(charsToProcess),
@@ -59,9 +65,21 @@ func NewTokenizer(inputData string) *Tokenizer {
 	}
 }
 
+// NewTokenizerWithConfig creates a tokenizer that enforces the token count and
+// string literal length limits of config
+func NewTokenizerWithConfig(inputData string, config *SpelParserConfiguration) *Tokenizer {
+	t := NewTokenizer(inputData)
+	t.maxTokenCount = config.MaxTokenCount
+	t.maxStringLiteralLength = config.MaxStringLiteralLength
+	return t
+}
+
 // Process tokenizes the input and returns a list of tokens
 func (t *Tokenizer) Process() ([]*Token, error) {
 	for t.pos < t.max {
+		if err := t.checkTokenCount(); err != nil {
+			return nil, err
+		}
 		ch := t.charsToProcess[t.pos]
 
 		if t.isAlphabetic(ch) {
@@ -142,7 +160,7 @@ func (t *Tokenizer) Process() ([]*Token, error) {
 				}
 			case '|':
 				if !t.isTwoCharToken(SYMBOLIC_OR) {
-					return nil, t.raiseParseException(t.pos, "MISSING_CHARACTER", "|")
+					return nil, t.raiseParseException(t.pos, MISSING_CHARACTER, "|")
 				}
 				t.pushPairToken(SYMBOLIC_OR)
 			case '?':
@@ -194,15 +212,38 @@ func (t *Tokenizer) Process() ([]*Token, error) {
 				// Hit sentinel at end of value
 				t.pos++ // will take us to the end
 			case '\\':
-				return nil, t.raiseParseException(t.pos, "UNEXPECTED_ESCAPE_CHAR")
+				return nil, t.raiseParseException(t.pos, UNEXPECTED_ESCAPE_CHAR)
 			default:
-				return nil, t.raiseParseException(t.pos+1, "UNSUPPORTED_CHARACTER", fmt.Sprintf("%c(%d)", ch, int(ch)))
+				return nil, t.raiseParseException(t.pos+1, UNSUPPORTED_CHARACTER, ch, int(ch))
 			}
 		}
 	}
+	if err := t.checkTokenCount(); err != nil {
+		return nil, err
+	}
+	for _, token := range t.tokens {
+		token.Span = t.source.Span(token.StartPos, token.EndPos)
+	}
 	return t.tokens, nil
 }
 
+// checkTokenCount fails once more tokens than maxTokenCount were produced
+func (t *Tokenizer) checkTokenCount() error {
+	if t.maxTokenCount > 0 && len(t.tokens) > t.maxTokenCount {
+		return t.raiseParseException(t.tokens[t.maxTokenCount].StartPos, MAX_TOKEN_COUNT_EXCEEDED, t.maxTokenCount)
+	}
+	return nil
+}
+
+// checkStringLiteralLength fails if the characters between the quotes of the
+// string literal ending before t.pos exceed maxStringLiteralLength
+func (t *Tokenizer) checkStringLiteralLength(start int) error {
+	if t.maxStringLiteralLength > 0 && t.pos-start-2 > t.maxStringLiteralLength {
+		return t.raiseParseException(start, MAX_STRING_LITERAL_LENGTH_EXCEEDED, t.maxStringLiteralLength)
+	}
+	return nil
+}
+
 // lexQuotedStringLiteral processes a single-quoted string literal
 func (t *Tokenizer) lexQuotedStringLiteral() error {
 	start := t.pos
@@ -211,7 +252,7 @@ func (t *Tokenizer) lexQuotedStringLiteral() error {
 	for !terminated {
 		t.pos++
 		if t.isExhausted() {
-			return t.raiseParseException(start, "NON_TERMINATING_QUOTED_STRING")
+			return t.raiseParseException(start, NON_TERMINATING_QUOTED_STRING)
 		}
 
 		ch := t.charsToProcess[t.pos]
@@ -226,6 +267,9 @@ func (t *Tokenizer) lexQuotedStringLiteral() error {
 	}
 
 	t.pos++
+	if err := t.checkStringLiteralLength(start); err != nil {
+		return err
+	}
 	data := t.subarray(start, t.pos)
 	t.tokens = append(t.tokens, NewTokenWithData(LITERAL_STRING, data, start, t.pos))
 	return nil
@@ -239,7 +283,7 @@ func (t *Tokenizer) lexDoubleQuotedStringLiteral() error {
 	for !terminated {
 		t.pos++
 		if t.isExhausted() {
-			return t.raiseParseException(start, "NON_TERMINATING_DOUBLE_QUOTED_STRING")
+			return t.raiseParseException(start, NON_TERMINATING_DOUBLE_QUOTED_STRING)
 		}
 
 		ch := t.charsToProcess[t.pos]
@@ -254,6 +298,9 @@ func (t *Tokenizer) lexDoubleQuotedStringLiteral() error {
 	}
 
 	t.pos++
+	if err := t.checkStringLiteralLength(start); err != nil {
+		return err
+	}
 	data := t.subarray(start, t.pos)
 	t.tokens = append(t.tokens, NewTokenWithData(LITERAL_STRING, data, start, t.pos))
 	return nil
@@ -326,7 +373,7 @@ func (t *Tokenizer) lexNumericLiteral(firstCharIsZero bool) error {
 	// Check for long suffix
 	if t.pos < len(t.charsToProcess) && t.isChar('L', 'l') {
 		if isReal {
-			return t.raiseParseException(start, "REAL_CANNOT_BE_LONG")
+			return t.raiseParseException(start, REAL_CANNOT_BE_LONG)
 		}
 		data := t.subarray(start, endOfNumber)
 		t.pushIntToken(data, true, start, endOfNumber)
@@ -430,9 +477,9 @@ func (t *Tokenizer) pushIntToken(data []rune, isLong bool, start, end int) {
 func (t *Tokenizer) pushHexIntToken(data []rune, isLong bool, start, end int) error {
 	if len(data) == 0 {
 		if isLong {
-			return t.raiseParseException(start, "NOT_A_LONG", t.expressionString[start:end+1])
+			return t.raiseParseException(start, NOT_A_LONG, string(t.charsToProcess[start:end+1]))
 		} else {
-			return t.raiseParseException(start, "NOT_AN_INTEGER", t.expressionString[start:end])
+			return t.raiseParseException(start, NOT_AN_INTEGER, string(t.charsToProcess[start:end]))
 		}
 	}
 
@@ -535,8 +582,8 @@ func (t *Tokenizer) isExhausted() bool {
 	return t.pos == t.max-1
 }
 
-func (t *Tokenizer) raiseParseException(start int, msg string, inserts ...interface{}) error {
-	return fmt.Errorf("parse exception at position %d: %s %v", start, msg, inserts)
+func (t *Tokenizer) raiseParseException(start int, message SpelMessage, inserts ...interface{}) error {
+	return newSpelParseException(t.source, start, message, inserts...)
 }
 
 // getTokenKindByName returns the TokenKind for a given operator name
@@ -0,0 +1,433 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+)
+
+// StandardTypeConverter is a registry of conversions, like Spring's
+// DefaultConversionService. Besides Go's assignability it converts
+//   - numbers to other number types, including *big.Int and *big.Float, failing
+//     if the value does not fit into the target type
+//   - strings to numbers and booleans, and numbers and booleans to strings
+//   - slices and arrays to slices or arrays of another element type, and maps to
+//     maps of other key and value types, converting each element
+//   - values of any type with a converter registered through RegisterConverter
+//
+// Registered converters take precedence over the built-in conversions, but not
+// over assignability.
+type StandardTypeConverter struct {
+	converters []registeredConverter
+}
+
+// registeredConverter is a user converter from sourceType to targetType
+type registeredConverter struct {
+	sourceType reflect.Type
+	targetType reflect.Type
+	convert    reflect.Value
+}
+
+// NewStandardTypeConverter creates the default type converter
+func NewStandardTypeConverter() *StandardTypeConverter {
+	return &StandardTypeConverter{}
+}
+
+// RegisterConverter adds a converter function of the form func(S) T or
+// func(S) (T, error). It is used for values assignable to S that must be
+// converted to T. A converter registered later for the same types replaces the
+// earlier one.
+func (c *StandardTypeConverter) RegisterConverter(converter interface{}) error {
+	fn, ok := converter.(reflect.Value)
+	if !ok {
+		fn = reflect.ValueOf(converter)
+	}
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return fmt.Errorf("cannot register converter: %T is not a function", converter)
+	}
+	signature := fn.Type()
+	if signature.NumIn() != 1 || signature.IsVariadic() || signature.NumOut() < 1 || signature.NumOut() > 2 ||
+		signature.NumOut() == 2 && signature.Out(1) != errorType {
+		return fmt.Errorf("cannot register converter: %v must be of the form func(S) T or func(S) (T, error)", signature)
+	}
+
+	registered := registeredConverter{sourceType: signature.In(0), targetType: signature.Out(0), convert: fn}
+	for i, existing := range c.converters {
+		if existing.sourceType == registered.sourceType && existing.targetType == registered.targetType {
+			c.converters[i] = registered
+			return nil
+		}
+	}
+	c.converters = append(c.converters, registered)
+	return nil
+}
+
+// findConverter returns the registered converter for the types, preferring one
+// registered for exactly sourceType over one for a type sourceType is assignable to
+func (c *StandardTypeConverter) findConverter(sourceType, targetType reflect.Type) (registeredConverter, bool) {
+	var assignable *registeredConverter
+	for i, converter := range c.converters {
+		if converter.targetType != targetType {
+			continue
+		}
+		if converter.sourceType == sourceType {
+			return converter, true
+		}
+		if assignable == nil && sourceType.AssignableTo(converter.sourceType) {
+			assignable = &c.converters[i]
+		}
+	}
+	if assignable == nil {
+		return registeredConverter{}, false
+	}
+	return *assignable, true
+}
+
+func (c *StandardTypeConverter) CanConvert(sourceType, targetType reflect.Type) bool {
+	if sourceType == nil {
+		return isNillableType(targetType)
+	}
+	if sourceType.AssignableTo(targetType) {
+		return true
+	}
+	if _, ok := c.findConverter(sourceType, targetType); ok {
+		return true
+	}
+
+	switch {
+	case isNumberType(sourceType) && isNumberType(targetType):
+		return true
+	case isStringConversion(sourceType, targetType):
+		return true
+	case isContainerKind(sourceType.Kind()) && isContainerKind(targetType.Kind()):
+		return c.canConvertElements(sourceType.Elem(), targetType.Elem())
+	case sourceType.Kind() == reflect.Map && targetType.Kind() == reflect.Map:
+		return c.canConvertElements(sourceType.Key(), targetType.Key()) &&
+			c.canConvertElements(sourceType.Elem(), targetType.Elem())
+	}
+	return isSafeConversion(sourceType, targetType)
+}
+
+// canConvertElements reports whether container elements can be converted. An
+// interface element type may hold anything, so whether its elements convert is
+// only known at conversion time.
+func (c *StandardTypeConverter) canConvertElements(sourceType, targetType reflect.Type) bool {
+	return sourceType.Kind() == reflect.Interface || c.CanConvert(sourceType, targetType)
+}
+
+func (c *StandardTypeConverter) ConvertValue(value interface{}, targetType reflect.Type) (interface{}, error) {
+	if isNull(value) {
+		if isNillableType(targetType) {
+			return reflect.Zero(targetType).Interface(), nil
+		}
+		return nil, fmt.Errorf("cannot convert null to %v", targetType)
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(targetType) {
+		return value, nil
+	}
+	if converter, ok := c.findConverter(v.Type(), targetType); ok {
+		return callConverter(converter, v)
+	}
+
+	switch {
+	case isNumberType(v.Type()) && isNumberType(targetType):
+		return convertNumber(value, targetType)
+	case isStringConversion(v.Type(), targetType):
+		return convertString(v, targetType)
+	case isContainerKind(v.Kind()) && isContainerKind(targetType.Kind()):
+		return c.convertElements(v, targetType)
+	case v.Kind() == reflect.Map && targetType.Kind() == reflect.Map:
+		return c.convertMap(v, targetType)
+	case isSafeConversion(v.Type(), targetType):
+		return v.Convert(targetType).Interface(), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to %v", value, targetType)
+}
+
+func callConverter(converter registeredConverter, value reflect.Value) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("converter from %v to %v panicked: %v", converter.sourceType, converter.targetType, r)
+		}
+	}()
+	if value.Type() != converter.sourceType {
+		value = value.Convert(converter.sourceType)
+	}
+	return invocationResult(converter.convert.Call([]reflect.Value{value}))
+}
+
+// convertElements converts each element of a slice or array to the element
+// type of a slice or array type. Arrays must have as many elements as the source.
+func (c *StandardTypeConverter) convertElements(value reflect.Value, targetType reflect.Type) (interface{}, error) {
+	length := value.Len()
+	var result reflect.Value
+	if targetType.Kind() == reflect.Array {
+		if targetType.Len() != length {
+			return nil, fmt.Errorf("cannot convert %d elements to %v", length, targetType)
+		}
+		result = reflect.New(targetType).Elem()
+	} else {
+		result = reflect.MakeSlice(targetType, length, length)
+	}
+
+	for i := 0; i < length; i++ {
+		element, err := c.convertElement(value.Index(i), targetType.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert element %d to %v: %v", i, targetType.Elem(), err)
+		}
+		result.Index(i).Set(element)
+	}
+	return result.Interface(), nil
+}
+
+// convertMap converts each key and value of a map to the key and element types
+// of a map type
+func (c *StandardTypeConverter) convertMap(value reflect.Value, targetType reflect.Type) (interface{}, error) {
+	result := reflect.MakeMapWithSize(targetType, value.Len())
+	iterator := value.MapRange()
+	for iterator.Next() {
+		key, err := c.convertElement(iterator.Key(), targetType.Key())
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert key %v to %v: %v", iterator.Key(), targetType.Key(), err)
+		}
+		element, err := c.convertElement(iterator.Value(), targetType.Elem())
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert value of key %v to %v: %v", iterator.Key(), targetType.Elem(), err)
+		}
+		result.SetMapIndex(key, element)
+	}
+	return result.Interface(), nil
+}
+
+func (c *StandardTypeConverter) convertElement(element reflect.Value, targetType reflect.Type) (reflect.Value, error) {
+	converted, err := c.ConvertValue(valueToInterface(element), targetType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if converted == nil {
+		return reflect.Zero(targetType), nil
+	}
+	return reflect.ValueOf(converted).Convert(targetType), nil
+}
+
+// isNillableType reports whether nil is a valid value of the given type
+func isNillableType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		return true
+	default:
+		return false
+	}
+}
+
+func isContainerKind(kind reflect.Kind) bool {
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// isNumberType reports whether values of the type are numbers: Go integers and
+// floats, *big.Int and *big.Float
+func isNumberType(typ reflect.Type) bool {
+	if typ == bigIntType || typ == bigFloatType {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSafeConversion reports whether reflect.Value.Convert can be used between the
+// types; integer to string is excluded since Go treats it as a rune conversion
+func isSafeConversion(sourceType, targetType reflect.Type) bool {
+	if targetType.Kind() == reflect.String && sourceType.Kind() != reflect.String {
+		return false
+	}
+	return sourceType.ConvertibleTo(targetType)
+}
+
+// isStringConversion reports whether the types are a string and a number or
+// boolean, which are converted by formatting or parsing the string
+func isStringConversion(sourceType, targetType reflect.Type) bool {
+	if sourceType.Kind() == reflect.String {
+		return targetType.Kind() == reflect.Bool || isNumberType(targetType)
+	}
+	return targetType.Kind() == reflect.String && (sourceType.Kind() == reflect.Bool || isNumberType(sourceType))
+}
+
+// convertNumber converts a number to another number type like Spring's
+// NumberUtils: floating point values are truncated when converted to integers,
+// and a value that does not fit into an integer target type is an overflow error
+func convertNumber(value interface{}, targetType reflect.Type) (interface{}, error) {
+	switch targetType {
+	case bigIntType:
+		if rank := numericRankOf(value); rank == floatRank || rank == doubleRank {
+			f, err := toBigFloat(value)
+			if err != nil {
+				return nil, err
+			}
+			value = f
+		}
+		return toBigInt(value), nil
+	case bigFloatType:
+		return toBigFloat(value)
+	}
+
+	result := reflect.New(targetType).Elem()
+	switch targetType.Kind() {
+	case reflect.Float32, reflect.Float64:
+		result.SetFloat(toFloat64(value))
+		return result.Interface(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := integerValue(value)
+		if !ok || result.OverflowInt(i) {
+			return nil, numberOverflow(value, targetType)
+		}
+		result.SetInt(i)
+		return result.Interface(), nil
+	default:
+		var u uint64
+		if isUnsigned(value) {
+			u = reflect.ValueOf(value).Uint()
+		} else if i, ok := integerValue(value); ok && i >= 0 {
+			u = uint64(i)
+		} else {
+			return nil, numberOverflow(value, targetType)
+		}
+		if result.OverflowUint(u) {
+			return nil, numberOverflow(value, targetType)
+		}
+		result.SetUint(u)
+		return result.Interface(), nil
+	}
+}
+
+// integerValue returns a number truncated to an int64, and false if it is out
+// of the int64 range
+func integerValue(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.Int64(), v.IsInt64()
+	case *big.Float:
+		if v.IsInf() {
+			return 0, false
+		}
+		i, _ := v.Int(nil)
+		return i.Int64(), i.IsInt64()
+	}
+	switch numericRankOf(value) {
+	case floatRank, doubleRank:
+		f := math.Trunc(toFloat64(value))
+		return int64(f), f >= math.MinInt64 && f < math.MaxInt64
+	}
+	if isUnsigned(value) {
+		u := reflect.ValueOf(value).Uint()
+		return int64(u), u <= math.MaxInt64
+	}
+	return toInt64(value), true
+}
+
+func numberOverflow(value interface{}, targetType reflect.Type) error {
+	return fmt.Errorf("cannot convert number %s of type %T to %v: overflow", formatNumber(value), value, targetType)
+}
+
+// convertString formats a number or boolean as a string, or parses a string
+// into a number or boolean of the target type. Numbers are formatted as in Java
+// and integers may be given in hexadecimal with a 0x or # prefix.
+func convertString(value reflect.Value, targetType reflect.Type) (interface{}, error) {
+	if targetType.Kind() == reflect.String {
+		text := fmt.Sprint(value.Interface())
+		if isNumberType(value.Type()) {
+			text = formatNumber(value.Interface())
+		}
+		return reflect.ValueOf(text).Convert(targetType).Interface(), nil
+	}
+
+	s := strings.TrimSpace(value.String())
+	switch targetType {
+	case bigIntType:
+		i, ok := new(big.Int).SetString(s, 0)
+		if !ok {
+			return nil, fmt.Errorf("cannot convert '%s' to %v", s, targetType)
+		}
+		return i, nil
+	case bigFloatType:
+		f, _, err := big.ParseFloat(s, 10, bigDecimalPrecision, big.ToNearestEven)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert '%s' to %v", s, targetType)
+		}
+		return f, nil
+	}
+
+	result := reflect.New(targetType).Elem()
+	switch targetType.Kind() {
+	case reflect.Bool:
+		b, err := parseBoolean(s)
+		if err != nil {
+			return nil, err
+		}
+		result.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		digits, base := integerDigits(s)
+		i, err := strconv.ParseInt(digits, base, targetType.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert '%s' to %v", s, targetType)
+		}
+		result.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		digits, base := integerDigits(s)
+		u, err := strconv.ParseUint(digits, base, targetType.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert '%s' to %v", s, targetType)
+		}
+		result.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, targetType.Bits())
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert '%s' to %v", s, targetType)
+		}
+		result.SetFloat(f)
+	}
+	return result.Interface(), nil
+}
+
+// integerDigits returns the digits of an integer string and their base, which is
+// 16 for the 0x, 0X and # prefixes Java's Integer.decode accepts
+func integerDigits(s string) (string, int) {
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	for _, prefix := range []string{"0x", "0X", "#"} {
+		if strings.HasPrefix(s, prefix) {
+			return sign + s[len(prefix):], 16
+		}
+	}
+	return sign + s, 10
+}
+
+// parseBoolean parses a boolean like Spring's StringToBooleanConverter, which
+// also accepts on/off, yes/no and 1/0
+func parseBoolean(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "on", "yes", "1":
+		return true, nil
+	case "false", "off", "no", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean value '%s'", s)
+	}
+}
@@ -0,0 +1,109 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TypeDescriptor is the value of a T(...) reference. It carries the Go type
+// values of the type have, if any, together with the "static" functions and
+// constants registered for the type, so that T(Math).max(1, 2) and
+// T(Integer).MAX_VALUE can be evaluated. Types such as java.lang.Math only
+// exist for their static members and have no Go type.
+type TypeDescriptor struct {
+	name      string
+	typ       reflect.Type
+	functions map[string][]reflect.Value
+	constants map[string]interface{}
+}
+
+// NewTypeDescriptor creates a descriptor for a type name and its Go type, which may be nil
+func NewTypeDescriptor(name string, typ reflect.Type) *TypeDescriptor {
+	return &TypeDescriptor{
+		name:      name,
+		typ:       typ,
+		functions: make(map[string][]reflect.Value),
+		constants: make(map[string]interface{}),
+	}
+}
+
+// Name returns the qualified name the type was registered under
+func (d *TypeDescriptor) Name() string {
+	return d.name
+}
+
+// Type returns the Go type of the type, or nil if it only has static members
+func (d *TypeDescriptor) Type() reflect.Type {
+	return d.typ
+}
+
+// RegisterFunction adds a static function. Registering several functions under
+// the same name overloads it; the best match for the arguments is invoked.
+func (d *TypeDescriptor) RegisterFunction(name string, function interface{}) error {
+	fn, ok := function.(reflect.Value)
+	if !ok {
+		fn = reflect.ValueOf(function)
+	}
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return fmt.Errorf("cannot register function '%s' on type '%s': %T is not a function", name, d.name, function)
+	}
+	d.functions[name] = append(d.functions[name], fn)
+	return nil
+}
+
+// RegisterConstant adds a static constant
+func (d *TypeDescriptor) RegisterConstant(name string, value interface{}) {
+	d.constants[name] = value
+}
+
+// LookupConstant returns the named constant, preferring an exact name match
+// over a case-insensitive one
+func (d *TypeDescriptor) LookupConstant(name string) (interface{}, bool) {
+	if value, ok := d.constants[name]; ok {
+		return value, true
+	}
+	for constantName, value := range d.constants {
+		if strings.EqualFold(constantName, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// findFunctions returns executors for the static functions matching name,
+// non-variadic functions first
+func (d *TypeDescriptor) findFunctions(name string) []*ReflectiveMethodExecutor {
+	var candidates []*ReflectiveMethodExecutor
+	for functionName, overloads := range d.functions {
+		if !strings.EqualFold(functionName, name) {
+			continue
+		}
+		for _, fn := range overloads {
+			candidates = append(candidates, &ReflectiveMethodExecutor{
+				name:      functionName,
+				signature: fn.Type(),
+				static:    true,
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return !candidates[i].signature.IsVariadic() && candidates[j].signature.IsVariadic()
+	})
+	return candidates
+}
+
+// lookupFunction returns the overload of the named function with the given signature
+func (d *TypeDescriptor) lookupFunction(name string, signature reflect.Type) (reflect.Value, bool) {
+	for _, fn := range d.functions[name] {
+		if fn.Type() == signature {
+			return fn, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func (d *TypeDescriptor) String() string {
+	return d.name
+}
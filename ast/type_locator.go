@@ -0,0 +1,330 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+var (
+	objectType = reflect.TypeOf((*interface{})(nil)).Elem()
+	intType    = reflect.TypeOf(int32(0))
+	listType   = reflect.TypeOf([]interface{}(nil))
+	mapType    = reflect.TypeOf(map[interface{}]interface{}(nil))
+)
+
+// defaultTypes maps the Java type names commonly used in expressions to the Go
+// types values of that kind have at runtime. Every Java wrapper is also
+// available under its primitive name.
+var defaultTypes = map[string]reflect.Type{
+	"java.lang.Object":    objectType,
+	"java.lang.String":    reflect.TypeOf(""),
+	"java.lang.Boolean":   reflect.TypeOf(false),
+	"java.lang.Integer":   intType,
+	"java.lang.Long":      reflect.TypeOf(int64(0)),
+	"java.lang.Short":     reflect.TypeOf(int16(0)),
+	"java.lang.Byte":      reflect.TypeOf(int8(0)),
+	"java.lang.Character": reflect.TypeOf(rune(0)),
+	"java.lang.Double":    reflect.TypeOf(float64(0)),
+	"java.lang.Float":     reflect.TypeOf(float32(0)),
+	"java.lang.Math":      nil,
+	"java.util.List":      listType,
+	"java.util.ArrayList": listType,
+	"java.util.Map":       mapType,
+	"java.util.HashMap":   mapType,
+	"boolean":             reflect.TypeOf(false),
+	"int":                 intType,
+	"long":                reflect.TypeOf(int64(0)),
+	"short":               reflect.TypeOf(int16(0)),
+	"byte":                reflect.TypeOf(int8(0)),
+	"char":                reflect.TypeOf(rune(0)),
+	"double":              reflect.TypeOf(float64(0)),
+	"float":               reflect.TypeOf(float32(0)),
+}
+
+// defaultFunctions are the static functions of the default types. Overloads
+// are listed in the order they are tried for close matches.
+var defaultFunctions = map[string]map[string][]interface{}{
+	"java.lang.Math": {
+		"max":    {mathMax},
+		"min":    {mathMin},
+		"abs":    {mathAbs},
+		"pow":    {math.Pow},
+		"sqrt":   {math.Sqrt},
+		"cbrt":   {math.Cbrt},
+		"floor":  {math.Floor},
+		"ceil":   {math.Ceil},
+		"round":  {func(a float64) int64 { return int64(math.Floor(a + 0.5)) }},
+		"log":    {math.Log},
+		"log10":  {math.Log10},
+		"exp":    {math.Exp},
+		"sin":    {math.Sin},
+		"cos":    {math.Cos},
+		"tan":    {math.Tan},
+		"random": {rand.Float64},
+	},
+	"java.lang.Integer": {
+		"parseInt": {parseInt32},
+		"valueOf":  {parseInt32, func(i int32) int32 { return i }},
+		"toString": {func(i int32) string { return strconv.FormatInt(int64(i), 10) }},
+	},
+	"java.lang.Long": {
+		"parseLong": {parseInt64},
+		"valueOf":   {parseInt64, func(l int64) int64 { return l }},
+		"toString":  {func(l int64) string { return strconv.FormatInt(l, 10) }},
+	},
+	"java.lang.Double": {
+		"parseDouble": {parseFloat64},
+		"valueOf":     {parseFloat64, func(d float64) float64 { return d }},
+		"isNaN":       {math.IsNaN},
+	},
+	"java.lang.Boolean": {
+		"parseBoolean": {func(s string) bool { b, _ := strconv.ParseBool(s); return b }},
+		"valueOf":      {func(s string) bool { b, _ := strconv.ParseBool(s); return b }, func(b bool) bool { return b }},
+	},
+	"java.lang.String": {
+		"valueOf": {func(value interface{}) string { return fmt.Sprint(value) }},
+	},
+}
+
+// defaultConstants are the static constants of the default types
+var defaultConstants = map[string]map[string]interface{}{
+	"java.lang.Math": {
+		"PI": math.Pi,
+		"E":  math.E,
+	},
+	"java.lang.Integer": {
+		"MAX_VALUE": int32(math.MaxInt32),
+		"MIN_VALUE": int32(math.MinInt32),
+	},
+	"java.lang.Long": {
+		"MAX_VALUE": int64(math.MaxInt64),
+		"MIN_VALUE": int64(math.MinInt64),
+	},
+	"java.lang.Short": {
+		"MAX_VALUE": int16(math.MaxInt16),
+		"MIN_VALUE": int16(math.MinInt16),
+	},
+	"java.lang.Byte": {
+		"MAX_VALUE": int8(math.MaxInt8),
+		"MIN_VALUE": int8(math.MinInt8),
+	},
+	"java.lang.Double": {
+		"MAX_VALUE":         math.MaxFloat64,
+		"MIN_VALUE":         math.SmallestNonzeroFloat64,
+		"POSITIVE_INFINITY": math.Inf(1),
+		"NEGATIVE_INFINITY": math.Inf(-1),
+		"NaN":               math.NaN(),
+	},
+	"java.lang.Float": {
+		"MAX_VALUE": float32(math.MaxFloat32),
+		"MIN_VALUE": float32(math.SmallestNonzeroFloat32),
+	},
+	"java.lang.Boolean": {
+		"TRUE":  true,
+		"FALSE": false,
+	},
+}
+
+// mathMax returns the larger of two numbers after binary numeric promotion, so
+// max(1, 2L) is a long and max(1, 2.5) a double
+func mathMax(a, b interface{}) (interface{}, error) {
+	return pickNumber(a, b, func(comparison int) bool { return comparison >= 0 })
+}
+
+// mathMin returns the smaller of two numbers, promoted like mathMax
+func mathMin(a, b interface{}) (interface{}, error) {
+	return pickNumber(a, b, func(comparison int) bool { return comparison <= 0 })
+}
+
+func pickNumber(a, b interface{}, pickFirst func(comparison int) bool) (interface{}, error) {
+	rank := promotedRank(a, b)
+	if rank == notNumeric {
+		return nil, fmt.Errorf("cannot compare %T and %T as numbers", a, b)
+	}
+
+	comparison, ordered := compareNumbers(a, b)
+	if !ordered {
+		// NaN is returned if either operand is NaN
+		return arithmetic('+', a, b)
+	}
+	picked := b
+	if pickFirst(comparison) {
+		picked = a
+	}
+	return promote(picked, rank)
+}
+
+// mathAbs returns the absolute value of a number. As in Java, the absolute
+// value of the most negative int or long is itself.
+func mathAbs(a interface{}) (interface{}, error) {
+	if !isNumber(a) {
+		return nil, fmt.Errorf("cannot take the absolute value of %T", a)
+	}
+	if comparison, _ := compareNumbers(a, int32(0)); comparison < 0 {
+		return negateNumber(a)
+	}
+	return promote(a, max(numericRankOf(a), intRank))
+}
+
+func parseInt32(s string) (int32, error) {
+	i, err := strconv.ParseInt(s, 10, 32)
+	return int32(i), err
+}
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseFloat64(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// StandardTypeLocator is a registry backed TypeLocator. Names that are not
+// registered are also looked up under each import prefix, so with the default
+// "java.lang" import T(String) finds java.lang.String.
+type StandardTypeLocator struct {
+	types   map[string]*TypeDescriptor
+	imports []string
+}
+
+// NewStandardTypeLocator creates a type locator knowing the default Java type
+// names and their static members, with java.lang imported
+func NewStandardTypeLocator() *StandardTypeLocator {
+	locator := NewEmptyTypeLocator()
+	locator.RegisterImport("java.lang")
+	for typeName, typ := range defaultTypes {
+		locator.RegisterType(typeName, typ)
+	}
+	for typeName, functions := range defaultFunctions {
+		descriptor := locator.types[typeName]
+		for name, overloads := range functions {
+			for _, function := range overloads {
+				descriptor.RegisterFunction(name, function)
+			}
+		}
+	}
+	for typeName, constants := range defaultConstants {
+		locator.RegisterConstants(typeName, constants)
+	}
+	return locator
+}
+
+// NewEmptyTypeLocator creates a type locator without default types or imports.
+// Only registered types can be found, which makes it an allowlist for
+// expressions from untrusted sources.
+func NewEmptyTypeLocator() *StandardTypeLocator {
+	return &StandardTypeLocator{
+		types: make(map[string]*TypeDescriptor),
+	}
+}
+
+// RegisterType makes a Go type available under the given type name and returns
+// its descriptor, to which static functions and constants can be added. The
+// static members of a type registered before are kept.
+func (l *StandardTypeLocator) RegisterType(typeName string, typ reflect.Type) *TypeDescriptor {
+	if descriptor, ok := l.types[typeName]; ok {
+		descriptor.typ = typ
+		return descriptor
+	}
+	descriptor := NewTypeDescriptor(typeName, typ)
+	l.types[typeName] = descriptor
+	return descriptor
+}
+
+// RegisterFunctions adds static functions to the named type, registering the
+// type without a Go type if it is unknown
+func (l *StandardTypeLocator) RegisterFunctions(typeName string, functions map[string]interface{}) error {
+	descriptor := l.descriptor(typeName)
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := descriptor.RegisterFunction(name, functions[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterConstants adds static constants to the named type, registering the
+// type without a Go type if it is unknown
+func (l *StandardTypeLocator) RegisterConstants(typeName string, constants map[string]interface{}) {
+	descriptor := l.descriptor(typeName)
+	for name, value := range constants {
+		descriptor.RegisterConstant(name, value)
+	}
+}
+
+func (l *StandardTypeLocator) descriptor(typeName string) *TypeDescriptor {
+	if descriptor, ok := l.types[typeName]; ok {
+		return descriptor
+	}
+	return l.RegisterType(typeName, nil)
+}
+
+// RegisterImport adds a prefix that is tried for unqualified type names
+func (l *StandardTypeLocator) RegisterImport(prefix string) {
+	l.imports = append(l.imports, prefix)
+}
+
+// RemoveImport removes a previously registered import prefix
+func (l *StandardTypeLocator) RemoveImport(prefix string) {
+	for i, imported := range l.imports {
+		if imported == prefix {
+			l.imports = append(l.imports[:i], l.imports[i+1:]...)
+			return
+		}
+	}
+}
+
+func (l *StandardTypeLocator) FindType(typeName string) (*TypeDescriptor, error) {
+	if descriptor, ok := l.types[typeName]; ok {
+		return descriptor, nil
+	}
+	for _, prefix := range l.imports {
+		if descriptor, ok := l.types[prefix+"."+typeName]; ok {
+			return descriptor, nil
+		}
+	}
+	return nil, NewSpelEvaluationException(positionUnknown, TYPE_NOT_FOUND, typeName)
+}
+
+// isInstanceOf reports whether value is an instance of typ. Besides exact and
+// interface matches a few Go specific rules apply: pointers match their element
+// type, every signed integer matches int (T(Integer)), and because the default
+// List and Map types are untyped containers, any slice or array matches
+// []interface{} and any map matches map[interface{}]interface{}.
+func isInstanceOf(value interface{}, typ reflect.Type) bool {
+	if isNull(value) || typ == nil {
+		return false
+	}
+
+	valueType := reflect.TypeOf(value)
+	switch {
+	case valueType == typ:
+		return true
+	case typ.Kind() == reflect.Interface:
+		return valueType.Implements(typ)
+	case valueType.Kind() == reflect.Ptr && valueType.Elem() == typ:
+		return true
+	}
+
+	switch typ {
+	case intType:
+		switch valueType.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return true
+		}
+	case listType:
+		return valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array
+	case mapType:
+		return valueType.Kind() == reflect.Map
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package ast
+
+import "reflect"
+
+// valueRef is an operand whose target has been evaluated once, so that it can
+// be read and then written without evaluating the operand again. It mirrors
+// Spring's ValueRef and is used by the increment and decrement operators.
+type valueRef interface {
+	isWritable() bool
+	getValue() (interface{}, error)
+	setValue(value interface{}) error
+}
+
+// valueRefProvider is implemented by nodes that evaluate part of themselves
+// before reaching the value they refer to
+type valueRefProvider interface {
+	getValueRef(state *ExpressionState) (valueRef, error)
+}
+
+// getValueRef resolves node against the active context object of state
+func getValueRef(state *ExpressionState, node SpelNode) (valueRef, error) {
+	if provider, ok := node.(valueRefProvider); ok {
+		return provider.getValueRef(state)
+	}
+	return &nodeValueRef{node: node, state: state, target: state.GetActiveContextObject()}, nil
+}
+
+// nodeValueRef reads and writes a node against the context object that was
+// active when it was resolved. It suits nodes such as property and variable
+// references that have nothing of their own to evaluate.
+type nodeValueRef struct {
+	node   SpelNode
+	state  *ExpressionState
+	target *TypedValue
+}
+
+func (r *nodeValueRef) isWritable() bool {
+	r.state.PushActiveContextObject(r.target)
+	defer r.state.PopActiveContextObject()
+	return r.node.IsWritable(r.state)
+}
+
+func (r *nodeValueRef) getValue() (interface{}, error) {
+	r.state.PushActiveContextObject(r.target)
+	defer r.state.PopActiveContextObject()
+	return r.node.GetValue(r.state)
+}
+
+func (r *nodeValueRef) setValue(value interface{}) error {
+	r.state.PushActiveContextObject(r.target)
+	defer r.state.PopActiveContextObject()
+	return r.node.SetValue(r.state, value)
+}
+
+// getValueRef evaluates every part but the last and resolves the last part
+// against the result
+func (c *CompoundExpression) getValueRef(state *ExpressionState) (valueRef, error) {
+	if len(c.Children) == 0 {
+		return &nodeValueRef{node: c, state: state, target: state.GetActiveContextObject()}, nil
+	}
+	var ref valueRef
+	err := c.withLastContext(state, func(last SpelNode) error {
+		var err error
+		ref, err = getValueRef(state, last)
+		return err
+	})
+	return ref, err
+}
+
+// indexerValueRef is an element of a target at an index that has already been
+// evaluated
+type indexerValueRef struct {
+	indexer *Indexer
+	state   *ExpressionState
+	target  interface{}
+	value   reflect.Value
+	index   interface{}
+}
+
+// getValueRef evaluates the index expression once. A null target is left to
+// the indexer, which is not writable on null.
+func (i *Indexer) getValueRef(state *ExpressionState) (valueRef, error) {
+	target := state.GetActiveContextObject().Value
+	if isNull(target) {
+		return &nodeValueRef{node: i, state: state, target: state.GetActiveContextObject()}, nil
+	}
+	value, _ := unwrapValue(reflect.ValueOf(target))
+	index, err := i.getIndex(state, value)
+	if err != nil {
+		return nil, err
+	}
+	return &indexerValueRef{indexer: i, state: state, target: target, value: value, index: index}, nil
+}
+
+func (r *indexerValueRef) isWritable() bool {
+	return isIndexWritable(r.value)
+}
+
+func (r *indexerValueRef) getValue() (interface{}, error) {
+	return r.indexer.getIndexedValue(r.state, r.target, r.value, r.index)
+}
+
+func (r *indexerValueRef) setValue(value interface{}) error {
+	return r.indexer.setIndexedValue(r.state, r.target, r.value, r.index, value)
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"log/slog"
+	"os"
 )
 
 // AST 分析工具 - 专门用于解析和显示 SpEL 表达式的 AST 结构
@@ -25,7 +27,10 @@ func runASTAnalyzer() {
 		"name matches '[A-Z].*'",
 	}
 
-	parser := ast.NewSpelExpressionParser()
+	// 通过 slog 输出 Token 流和节点构造过程
+	config := ast.NewSpelParserConfiguration()
+	config.Tracer = ast.NewSlogParseTracer(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	parser := ast.NewSpelExpressionParserWithConfig(config)
 
 	for i, expr := range testExpressions {
 		fmt.Printf("\n%d. 表达式: %s\n", i+1, expr)
@@ -36,8 +41,7 @@ func runASTAnalyzer() {
 func analyzeExpression(parser *ast.SpelExpressionParser, expression string) {
 	fmt.Printf("Token 流:\n")
 
-	// 使用调试解析方法
-	expr, err := parser.DoParseExpression(expression)
+	expr, err := parser.ParseExpression(expression)
 	if err != nil {
 		fmt.Printf("❌ 解析错误: %v\n", err)
 		return
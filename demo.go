@@ -6,12 +6,54 @@ import (
 	"log"
 )
 
+// demoProfile 和 demoUser 为演示表达式提供嵌套属性
+type demoProfile struct {
+	Name  string
+	Email string
+}
+
+type demoUser struct {
+	Profile *demoProfile
+}
+
+// demoRoot 是演示表达式求值时使用的根对象
+type demoRoot struct {
+	Age   int
+	IsVip bool
+	User  *demoUser
+}
+
+func (r *demoRoot) GetValue() string {
+	return "root value"
+}
+
+// demoUserService 是演示 Bean 引用时注册的服务
+type demoUserService struct {
+	users map[int]*demoUser
+}
+
+func (s *demoUserService) FindById(id int) (*demoUser, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("用户 %d 不存在", id)
+	}
+	return user, nil
+}
+
 // 演示 SpEL 解析器的高级功能
 func demonstrateAdvancedFeatures() {
 	fmt.Println("高级 SpEL 功能演示")
 	fmt.Println("==================")
 
 	parser := ast.NewSpelExpressionParser()
+	root := &demoRoot{
+		Age:  30,
+		User: &demoUser{Profile: &demoProfile{Name: "Alice", Email: "alice@example.com"}},
+	}
+	context := ast.NewStandardEvaluationContextWithRoot(root)
+	context.SetBeanResolver(ast.NewMapBeanResolver(map[string]interface{}{
+		"userService": &demoUserService{users: map[int]*demoUser{123: root.User}},
+	}))
 
 	// 测试各种表达式类型
 	examples := map[string]string{
@@ -40,7 +82,7 @@ func demonstrateAdvancedFeatures() {
 
 		fmt.Printf("AST结构: %s\n", expr.ToStringAST())
 
-		value, err := expr.GetValue()
+		value, err := expr.GetValueWithContext(context)
 		if err != nil {
 			fmt.Printf("求值错误: %v\n", err)
 		} else {
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"github.com/weaweawe01/ParserSpel/ast"
+	"log/slog"
+	"os"
 	"strings"
 )
 
@@ -19,12 +21,14 @@ func demonstrateComplexAST() {
 	fmt.Printf("📊 预期运算顺序: 2 + (3 * 4) + ((5 - 1) * 2)\n")
 	fmt.Printf("🎯 预期结果: 2 + 12 + 8 = 22\n\n")
 
-	parser := ast.NewSpelExpressionParser()
+	config := ast.NewSpelParserConfiguration()
+	config.Tracer = ast.NewSlogParseTracer(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	parser := ast.NewSpelExpressionParserWithConfig(config)
 
 	fmt.Println("🔍 Token 分析:")
 	fmt.Println("-------------")
 
-	expr, err := parser.DoParseExpression(expression)
+	expr, err := parser.ParseExpression(expression)
 	if err != nil {
 		fmt.Printf("❌ 解析失败: %v\n", err)
 		return
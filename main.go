@@ -18,7 +18,7 @@ func main() {
 		tokenizer := ast.NewTokenizer(expr)
 		tokens, err := tokenizer.Process()
 		if err != nil {
-			fmt.Errorf("tokenization failed: %v", err)
+			fmt.Printf("tokenization failed: %v\n", err)
 			return
 		}
 		fmt.Println("词法序列Token:")
@@ -31,6 +31,11 @@ func main() {
 			fmt.Printf("❌ 解析错误: %v\n", err)
 		} else {
 			ast.PrintASTWithTitle(result.AST, "完整 AST 树形结构")
+			if value, err := result.GetValue(); err != nil {
+				fmt.Printf("求值错误: %v\n", err)
+			} else {
+				fmt.Printf("求值结果: %v\n", value)
+			}
 		}
 	}
 }
@@ -379,8 +379,8 @@ func TestSpelParserMapAnalysis(t *testing.T) {
 			name:       "工厂Bean引用",
 			expression: "&foo",
 			expected: ASTExpectation{
-				NodeType: "BeanReference", // 注意&foo实际被解析为BeanReference
-				Value:    "@foo",          // 注意实际显示为@foo
+				NodeType: "BeanReference",
+				Value:    "&foo",
 				Children: []ASTExpectation{},
 			},
 		},
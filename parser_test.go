@@ -767,6 +767,8 @@ func getNodeTypeName(node ast.SpelNode) string {
 		return "OpEQ"
 	case *ast.OpNE:
 		return "OpNE"
+	case *ast.OperatorInstanceof:
+		return "OperatorInstanceof"
 	case *ast.OpAnd:
 		return "OpAnd"
 	case *ast.OpOr: